@@ -9,9 +9,6 @@ class Localbeach < Formula
   version "{{VERSION}}"
   license "GPL-3.0-or-later"
 
-  depends_on "mkcert"
-  depends_on "nss"
-
   on_macos do
     on_intel do
       url "https://github.com/flownative/localbeach/releases/download/v{{VERSION}}/beach_darwin_amd64.zip"
@@ -0,0 +1,340 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/certificates"
+	"github.com/flownative/localbeach/pkg/container"
+	"github.com/flownative/localbeach/pkg/path"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const certificateRenewalPeriod = 30 * 24 * time.Hour
+
+var certRenewForce bool
+var certExportToPhp bool
+
+// certCmd represents the cert command
+var certCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "Manage the HTTPS certificates of Local Beach",
+	Long: `cert
+
+Local Beach manages its own certificate authority, which is created and
+installed into the system trust store by "beach setup-https". Certificates
+are stored in the Local Beach certificates directory and picked up by the
+reverse proxy automatically, if they are named after a virtual host.
+`,
+}
+
+var certListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the certificates used by the reverse proxy",
+	Args:  cobra.ExactArgs(0),
+	Run:   handleCertListRun,
+}
+
+var certIssueCmd = &cobra.Command{
+	Use:   "issue [host...]",
+	Short: "Issue certificates for the given hosts, defaults to the virtual hosts of the Local Beach instance in the current directory",
+	Run:   handleCertIssueRun,
+}
+
+var certRenewCmd = &cobra.Command{
+	Use:   "renew",
+	Short: "Renew certificates issued by the Local Beach certificate authority which expire soon",
+	Args:  cobra.ExactArgs(0),
+	Run:   handleCertRenewRun,
+}
+
+var certExportCaCmd = &cobra.Command{
+	Use:   "export-ca [file]",
+	Short: "Export the certificate of the Local Beach certificate authority",
+	Long: `export-ca
+
+This command writes the certificate of the Local Beach certificate authority
+to the given file, or stdout if no file is given. Import it into browsers
+which don't use the system trust store, like Firefox: open the certificate
+settings ("about:preferences#privacy", "View Certificates"), choose
+"Import" on the "Authorities" tab and trust it for identifying websites.
+Alternatively, set "security.enterprise_roots.enabled" to true in
+"about:config", so that Firefox trusts the certificate authorities of the
+system trust store.
+
+With --php, the certificate is installed into the trust store of the PHP
+container of the Local Beach instance in the current directory instead, so
+that PHP can connect to other Local Beach instances via HTTPS. This needs to
+be repeated when the container was recreated.
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  handleCertExportCaRun,
+}
+
+func init() {
+	certRenewCmd.Flags().BoolVarP(&certRenewForce, "force", "f", false, "Renew all certificates issued by the Local Beach certificate authority")
+	certExportCaCmd.Flags().BoolVar(&certExportToPhp, "php", false, "Install the certificate into the PHP container of the Local Beach instance in the current directory")
+	certCmd.AddCommand(certListCmd)
+	certCmd.AddCommand(certIssueCmd)
+	certCmd.AddCommand(certRenewCmd)
+	certCmd.AddCommand(certExportCaCmd)
+	rootCmd.AddCommand(certCmd)
+}
+
+func handleCertListRun(cmd *cobra.Command, args []string) {
+	authority, err := loadCertificateAuthority()
+	if err != nil && !errors.Is(err, certificates.ErrNoAuthorityFound) {
+		log.Fatal(err)
+		return
+	}
+
+	certificateList, err := certificates.List(path.Certificates, authority)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if len(certificateList) == 0 {
+		log.Info("There are no certificates in " + path.Certificates)
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "NAME\tHOSTS\tEXPIRES\tISSUER\tSTATUS")
+	for _, certificate := range certificateList {
+		issuer := "other"
+		if certificate.IssuedByAuthority {
+			issuer = "Local Beach CA"
+		}
+		_, _ = fmt.Fprintf(writer, "%v\t%v\t%v\t%v\t%v\n", certificate.Name, strings.Join(certificate.Hosts, ","), certificate.NotAfter.Format("2006-01-02"), issuer, getCertificateStatus(certificate))
+	}
+	_ = writer.Flush()
+	return
+}
+
+func handleCertIssueRun(cmd *cobra.Command, args []string) {
+	authority, err := loadCertificateAuthority()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	hosts := args
+	if len(hosts) == 0 {
+		sandbox, err := beachsandbox.GetActiveSandbox()
+		if err != nil && sandbox == nil {
+			log.Fatal("Could not activate sandbox: ", err)
+			return
+		}
+		hosts = sandbox.Config.VirtualHosts
+	}
+
+	for _, host := range hosts {
+		err = authority.Issue(path.Certificates, certificates.FilenameForHost(host), []string{host})
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		log.Info("Issued certificate for " + host)
+	}
+
+	err = reloadReverseProxy()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	return
+}
+
+func handleCertRenewRun(cmd *cobra.Command, args []string) {
+	authority, err := loadCertificateAuthority()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	certificateList, err := certificates.List(path.Certificates, authority)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	renewed := 0
+	for _, certificate := range certificateList {
+		if !certificate.IssuedByAuthority {
+			log.Debug("Skipped " + certificate.Name + " (not issued by the Local Beach certificate authority)")
+			continue
+		}
+		if !certRenewForce && time.Until(certificate.NotAfter) > certificateRenewalPeriod {
+			log.Debug("Skipped " + certificate.Name + " (valid until " + certificate.NotAfter.Format("2006-01-02") + ")")
+			continue
+		}
+		err = authority.Issue(path.Certificates, certificate.Name, certificate.Hosts)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		log.Info("Renewed certificate " + certificate.Name)
+		renewed++
+	}
+
+	if renewed == 0 {
+		log.Info("No certificates needed to be renewed")
+		return
+	}
+
+	err = reloadReverseProxy()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	return
+}
+
+func handleCertExportCaRun(cmd *cobra.Command, args []string) {
+	authority, err := loadCertificateAuthority()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	if certExportToPhp {
+		sandbox, err := beachsandbox.GetActiveSandbox()
+		if err != nil {
+			log.Fatal("Could not activate sandbox: ", err)
+			return
+		}
+
+		certificatePEM, err := os.ReadFile(authority.CertificatePathAndFilename)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		containerName := sandbox.ProjectName + "_php"
+		err = installCertificateAuthorityInContainer(containerName, certificatePEM)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		log.Info("Installed the certificate authority into container " + containerName)
+		return
+	}
+
+	certificatePEM, err := os.ReadFile(authority.CertificatePathAndFilename)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if len(args) == 0 || args[0] == "-" {
+		_, _ = os.Stdout.Write(certificatePEM)
+		return
+	}
+	err = os.WriteFile(args[0], certificatePEM, 0644)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	log.Info("Exported the certificate authority to " + args[0])
+	return
+}
+
+// provisionCertificates issues certificates for those virtual hosts of the given sandbox, which are not
+// covered by an existing certificate yet. It returns the hosts certificates were issued for.
+func provisionCertificates(sandbox *beachsandbox.BeachSandbox) ([]string, error) {
+	authority, err := loadCertificateAuthority()
+	if errors.Is(err, certificates.ErrNoAuthorityFound) {
+		log.Debug("Skipping certificate provisioning, run \"beach setup-https\" to enable HTTPS")
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	certificateList, err := certificates.List(path.Certificates, authority)
+	if err != nil {
+		return nil, err
+	}
+
+	var issuedHosts []string
+	for _, host := range sandbox.Config.VirtualHosts {
+		if certificates.IsHostCovered(certificateList, host) {
+			continue
+		}
+		err = authority.Issue(path.Certificates, certificates.FilenameForHost(host), []string{host})
+		if err != nil {
+			return issuedHosts, err
+		}
+		issuedHosts = append(issuedHosts, host)
+	}
+	return issuedHosts, nil
+}
+
+// loadCertificateAuthority loads the Local Beach certificate authority, after moving it out of the certificates
+// directory if it was created by an earlier version of Local Beach.
+func loadCertificateAuthority() (*certificates.Authority, error) {
+	err := migrateCertificateAuthority()
+	if err != nil {
+		return nil, err
+	}
+	return certificates.LoadAuthority(path.CertificateAuthority)
+}
+
+// migrateCertificateAuthority moves the certificate authority from the certificates directory, which is mounted
+// into the reverse proxy container, to its own directory.
+func migrateCertificateAuthority() error {
+	return certificates.MigrateAuthority(filepath.Join(path.Certificates, "CA"), path.CertificateAuthority)
+}
+
+func getCertificateStatus(certificate certificates.Certificate) string {
+	switch {
+	case time.Now().After(certificate.NotAfter):
+		return "expired"
+	case time.Until(certificate.NotAfter) < certificateRenewalPeriod:
+		return "expires soon"
+	}
+	return "valid"
+}
+
+// installCertificateAuthorityInContainer adds the given CA certificate to the trusted certificates of the given
+// container, which needs to provide update-ca-certificates
+func installCertificateAuthorityInContainer(containerName string, certificatePEM []byte) error {
+	commands := []container.ExecOptions{
+		{Command: []string{"sh", "-c", "cat > /usr/local/share/ca-certificates/localbeach-rootCA.crt"}, Stdin: bytes.NewReader(certificatePEM)},
+		{Command: []string{"update-ca-certificates"}},
+	}
+	for _, options := range commands {
+		output := new(bytes.Buffer)
+		options.User = "root"
+		options.Stdout = output
+		options.Stderr = output
+		exitCode, err := getContainerClient().Exec(context.Background(), containerName, options)
+		if err != nil {
+			return err
+		}
+		if exitCode != 0 {
+			return fmt.Errorf("failed installing the certificate authority into container %v: %v", containerName, strings.TrimSpace(output.String()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var configSetLocal bool
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and change the configuration of the Local Beach instance in the current directory",
+	Long: `config
+
+The configuration of a Local Beach instance is loaded from the following
+files in the project root, values of later files take precedence:
+
+ - .localbeach.dist.env (usually committed)
+ - .localbeach.env (local overrides, usually not committed)
+ - .env
+`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Display the effective configuration values and where they are defined",
+	Args:  cobra.ExactArgs(0),
+	Run:   handleConfigShowRun,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get KEY",
+	Short: "Display the effective value of the given configuration key",
+	Args:  cobra.ExactArgs(1),
+	Run:   handleConfigGetRun,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Set the given configuration key in .localbeach.dist.env, or in .localbeach.env with --local",
+	Args:  cobra.ExactArgs(2),
+	Run:   handleConfigSetRun,
+}
+
+func init() {
+	configSetCmd.Flags().BoolVar(&configSetLocal, "local", false, "Write the value into .localbeach.env instead of the committed .localbeach.dist.env")
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func handleConfigShowRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil && sandbox == nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+	if sandbox.Config == nil {
+		log.Fatal("Could not load configuration: ", err)
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "KEY\tVALUE\tSOURCE")
+	for _, value := range sandbox.Config.Values() {
+		source := formatConfigValueSource(sandbox, value)
+		for _, overriddenValue := range value.Overrides {
+			source += ", overrides " + formatConfigValueSource(sandbox, overriddenValue)
+		}
+		_, _ = fmt.Fprintf(writer, "%v\t%v\t%v\n", value.Key, strings.ReplaceAll(value.Value, "\n", "\\n"), source)
+	}
+	_ = writer.Flush()
+	return
+}
+
+func handleConfigGetRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil && sandbox == nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+	if sandbox.Config == nil {
+		log.Fatal("Could not load configuration: ", err)
+		return
+	}
+
+	value, ok := sandbox.Config.Lookup(args[0])
+	if !ok {
+		log.Fatal(args[0] + " is not defined")
+		return
+	}
+	fmt.Println(value.Value)
+	return
+}
+
+func handleConfigSetRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil && sandbox == nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	filename := ".localbeach.dist.env"
+	if configSetLocal {
+		filename = ".localbeach.env"
+	}
+
+	key, value := args[0], args[1]
+	err = beachsandbox.SetConfigValue(filepath.Join(sandbox.ProjectRootPath, filename), key, value)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	log.Info(fmt.Sprintf("Set %v in %v", key, filename))
+
+	config, err := beachsandbox.LoadConfig(sandbox.ProjectRootPath)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if effectiveValue, ok := config.Lookup(key); ok && filepath.Base(effectiveValue.Filename) != filename {
+		log.Warn(fmt.Sprintf("%v is overridden by %v", key, formatConfigValueSource(sandbox, effectiveValue)))
+	}
+	return
+}
+
+func formatConfigValueSource(sandbox *beachsandbox.BeachSandbox, value beachsandbox.ConfigValue) string {
+	filename, err := filepath.Rel(sandbox.ProjectRootPath, value.Filename)
+	if err != nil {
+		filename = value.Filename
+	}
+	return fmt.Sprintf("%v:%d", filename, value.Line)
+}
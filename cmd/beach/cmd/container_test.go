@@ -0,0 +1,255 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flownative/localbeach/pkg/config"
+	"github.com/flownative/localbeach/pkg/container"
+)
+
+// useFakeContainerClient replaces the container runtime and client by a fake knowing the given containers for the
+// duration of the current test
+func useFakeContainerClient(t *testing.T, containers ...container.Container) *container.Fake {
+	t.Helper()
+
+	fake := container.NewFake(containers...)
+	previousRuntime, previousClient := containerRuntime, containerClient
+	containerRuntime = &container.Runtime{Name: container.RuntimeDocker, Command: "docker"}
+	containerClient = fake
+	t.Cleanup(func() {
+		containerRuntime, containerClient = previousRuntime, previousClient
+	})
+	return fake
+}
+
+func TestIsContainerRunning(t *testing.T) {
+	useFakeContainerClient(t,
+		container.Container{ID: "1", Name: "running", State: "running"},
+		container.Container{ID: "2", Name: "exited", State: "exited"},
+	)
+
+	tests := []struct {
+		name          string
+		containerName string
+		expected      bool
+	}{
+		{name: "running container", containerName: "running", expected: true},
+		{name: "exited container", containerName: "exited", expected: false},
+		{name: "unknown container", containerName: "unknown", expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			running, err := isContainerRunning(test.containerName)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if running != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, running)
+			}
+		})
+	}
+}
+
+func TestIsContainerReady(t *testing.T) {
+	useFakeContainerClient(t,
+		container.Container{ID: "1", Name: "without-healthcheck", State: "running", Health: container.HealthNone},
+		container.Container{ID: "2", Name: "healthy", State: "running", Health: container.HealthHealthy},
+		container.Container{ID: "3", Name: "starting", State: "running", Health: container.HealthStarting},
+		container.Container{ID: "4", Name: "created", State: "created"},
+		container.Container{ID: "5", Name: "exited", State: "exited"},
+		container.Container{ID: "6", Name: "dead", State: "dead"},
+	)
+
+	tests := []struct {
+		name          string
+		containerName string
+		expected      bool
+		expectError   bool
+	}{
+		{name: "running without healthcheck", containerName: "without-healthcheck", expected: true},
+		{name: "running and healthy", containerName: "healthy", expected: true},
+		{name: "health check still starting", containerName: "starting", expected: false},
+		{name: "not started yet", containerName: "created", expected: false},
+		{name: "exited", containerName: "exited", expectError: true},
+		{name: "dead", containerName: "dead", expectError: true},
+		{name: "unknown container", containerName: "unknown", expectError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ready, err := isContainerReady(test.containerName)
+			if test.expectError {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, ready)
+			}
+		})
+	}
+}
+
+func TestFindInstanceRoots(t *testing.T) {
+	firstProject := t.TempDir()
+	secondProject := t.TempDir()
+	otherProject := t.TempDir()
+	for _, projectPath := range []string{firstProject, secondProject} {
+		err := os.WriteFile(filepath.Join(projectPath, ".localbeach.docker-compose.yaml"), []byte{}, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	composeLabels := func(projectPath string) map[string]string {
+		return map[string]string{
+			"com.docker.compose.project.config_files": filepath.Join(projectPath, ".localbeach.docker-compose.yaml"),
+		}
+	}
+	useFakeContainerClient(t,
+		container.Container{ID: "1", Name: "first_php", State: "running", Networks: []string{"local_beach"}, Labels: composeLabels(firstProject)},
+		container.Container{ID: "2", Name: "first_webserver", State: "running", Networks: []string{"local_beach"}, Labels: composeLabels(firstProject)},
+		container.Container{ID: "3", Name: "second_php", State: "running", Networks: []string{"local_beach", "other"}, Labels: composeLabels(secondProject)},
+		container.Container{ID: "4", Name: "stopped_php", State: "exited", Networks: []string{"local_beach"}, Labels: composeLabels(t.TempDir())},
+		container.Container{ID: "5", Name: "other_php", State: "running", Networks: []string{"local_beach"}, Labels: composeLabels(otherProject)},
+		container.Container{ID: "6", Name: "unrelated", State: "running", Networks: []string{"other"}, Labels: composeLabels(t.TempDir())},
+		container.Container{ID: "7", Name: "local_beach_nginx", State: "running", Networks: []string{"local_beach"}},
+	)
+
+	instanceRoots, err := findInstanceRoots()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{firstProject, secondProject}
+	if !reflect.DeepEqual(instanceRoots, expected) {
+		t.Errorf("expected %v, got %v", expected, instanceRoots)
+	}
+}
+
+func TestWaitForDatabaseServer(t *testing.T) {
+	previousTimeout := config.Current.Database.StartupTimeout
+	t.Cleanup(func() {
+		config.Current.Database.StartupTimeout = previousTimeout
+	})
+	config.Current.Database.StartupTimeout = time.Nanosecond
+
+	server := &databaseServer{Image: "mariadb:11", ContainerName: "local_beach_database", DataPath: "/data/MariaDB"}
+
+	tests := []struct {
+		name          string
+		container     container.Container
+		logOutput     string
+		expectedError string
+	}{
+		{
+			name:      "healthy",
+			container: container.Container{ID: "1", Name: "local_beach_database", State: "running", Health: container.HealthHealthy},
+		},
+		{
+			name:          "stopped with permission problem",
+			container:     container.Container{ID: "1", Name: "local_beach_database", State: "exited"},
+			logOutput:     "[ERROR] Can't create/write to file '/var/lib/mysql/is_writable' (Errcode: 13 \"Permission denied\")\n",
+			expectedError: "is not allowed to access its data directory /data/MariaDB",
+		},
+		{
+			name:          "stopped with corrupt data",
+			container:     container.Container{ID: "1", Name: "local_beach_database", State: "dead"},
+			logOutput:     "[ERROR] InnoDB: Database page corruption on disk or a failed file read\n",
+			expectedError: "seems to be corrupt",
+		},
+		{
+			name:          "stopped for unknown reasons",
+			container:     container.Container{ID: "1", Name: "local_beach_database", State: "exited"},
+			expectedError: "the database server stopped unexpectedly",
+		},
+		{
+			name:          "timeout",
+			container:     container.Container{ID: "1", Name: "local_beach_database", State: "running", Health: container.HealthStarting},
+			expectedError: "timeout waiting for database server to start",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := useFakeContainerClient(t, test.container)
+			fake.LogOutput[test.container.Name] = test.logOutput
+
+			err := waitForDatabaseServer(server, time.Now())
+			if len(test.expectedError) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected an error containing %q, got none", test.expectedError)
+			}
+			if !strings.Contains(err.Error(), test.expectedError) {
+				t.Errorf("expected an error containing %q, got %q", test.expectedError, err.Error())
+			}
+		})
+	}
+
+	t.Run("unknown container", func(t *testing.T) {
+		useFakeContainerClient(t)
+		if err := waitForDatabaseServer(server, time.Now()); err == nil {
+			t.Fatal("expected an error, got none")
+		}
+	})
+}
+
+func TestInstallCertificateAuthorityInContainer(t *testing.T) {
+	fake := useFakeContainerClient(t, container.Container{ID: "1", Name: "acme_php", State: "running"})
+	var installedPEM []byte
+	fake.ExecHandler = func(nameOrID string, options container.ExecOptions) (int, error) {
+		if options.User != "root" {
+			t.Errorf("expected commands to run as root, got %q", options.User)
+		}
+		if options.Stdin != nil {
+			installedPEM, _ = io.ReadAll(options.Stdin)
+		}
+		return 0, nil
+	}
+
+	certificatePEM := []byte("-----BEGIN CERTIFICATE-----\n...\n-----END CERTIFICATE-----\n")
+	if err := installCertificateAuthorityInContainer("acme_php", certificatePEM); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(installedPEM, certificatePEM) {
+		t.Errorf("expected the certificate to be written to the container, got %q", installedPEM)
+	}
+	if len(fake.Executions) != 2 || !reflect.DeepEqual(fake.Executions[1].Command, []string{"update-ca-certificates"}) {
+		t.Errorf("expected update-ca-certificates to run after writing the certificate, got %v", fake.Executions)
+	}
+
+	fake.ExecHandler = func(nameOrID string, options container.ExecOptions) (int, error) {
+		_, _ = options.Stderr.Write([]byte("sh: can't create /usr/local/share/ca-certificates/localbeach-rootCA.crt: nonexistent directory\n"))
+		return 1, nil
+	}
+	err := installCertificateAuthorityInContainer("acme_php", certificatePEM)
+	if err == nil || !strings.Contains(err.Error(), "nonexistent directory") {
+		t.Errorf("expected the output of the failed command in the error, got %v", err)
+	}
+}
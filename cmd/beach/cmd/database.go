@@ -0,0 +1,673 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/config"
+	"github.com/flownative/localbeach/pkg/container"
+	"github.com/flownative/localbeach/pkg/exec"
+	"github.com/flownative/localbeach/pkg/path"
+	"github.com/flownative/localbeach/pkg/registry"
+	log "github.com/sirupsen/logrus"
+)
+
+const databaseContainerName = "local_beach_database"
+const postgresContainerName = "local_beach_postgres"
+
+// databaseUsernameMaximumLength is the maximum length of user names supported by MySQL, MariaDB allows longer ones
+const databaseUsernameMaximumLength = 32
+
+// databaseCharacterSetOptions match the character set and collation on the command line of the MariaDB servers
+const databaseCharacterSetOptions = "CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci"
+
+var databaseUsernameInvalidCharacters = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// remoteDatabaseConnectionOptions are the client options for connecting to the database of a Beach instance,
+// they are expanded by the shell of the instance from its environment
+const remoteDatabaseConnectionOptions = `-h "$BEACH_DATABASE_HOST" -P "${BEACH_DATABASE_PORT:-3306}" -u "$BEACH_DATABASE_USERNAME" --password="$BEACH_DATABASE_PASSWORD"`
+
+var databaseServerNamePattern = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// databaseServer is a shared database server. Besides the default server, there is an additional server for
+// each database image used by a project, with its own data directory, and the PostgreSQL server, if a project
+// uses PostgreSQL. Port is the port of the server within the Local Beach network.
+type databaseServer struct {
+	Driver        string
+	Image         string
+	ServiceName   string
+	ContainerName string
+	DataPath      string
+	Port          int
+}
+
+// getDatabaseServer returns the shared database server running the given image, an empty image means the
+// default server
+func getDatabaseServer(image string) *databaseServer {
+	if len(image) == 0 || image == config.Current.Database.Image {
+		return &databaseServer{
+			Driver:        beachsandbox.DatabaseDriverMySQL,
+			Image:         config.Current.Database.Image,
+			ServiceName:   "database",
+			ContainerName: databaseContainerName,
+			DataPath:      path.Database,
+			Port:          3306,
+		}
+	}
+
+	name := strings.Trim(databaseServerNamePattern.ReplaceAllString(strings.ToLower(image), "-"), "-")
+	return &databaseServer{
+		Driver:        beachsandbox.DatabaseDriverMySQL,
+		Image:         image,
+		ServiceName:   "database_" + name,
+		ContainerName: databaseContainerName + "_" + name,
+		// The directory is a sibling of the data directory of the default server, which would take every
+		// directory within its own data directory for a database
+		DataPath: path.Database + "-" + name,
+		Port:     3306,
+	}
+}
+
+// migrateDatabaseServerDataPath moves the data of the given additional database server out of the data directory
+// of the default server, where earlier versions of Local Beach kept it
+func migrateDatabaseServerDataPath(server *databaseServer) error {
+	name := strings.TrimPrefix(server.DataPath, path.Database+"-")
+	if server.Driver != beachsandbox.DatabaseDriverMySQL || server.IsDefault() || name == server.DataPath {
+		return nil
+	}
+	previousServersPath := filepath.Join(path.Database, ".servers")
+	previousDataPath := filepath.Join(previousServersPath, name)
+	if _, err := os.Stat(previousDataPath); err != nil {
+		return nil
+	}
+	if _, err := os.Stat(server.DataPath); err == nil {
+		return errors.New("the data of database server " + server.ContainerName + " exists in " + previousDataPath + " and " + server.DataPath + ", remove one of them")
+	}
+
+	log.Info("Moving data of database server " + server.ContainerName + " to " + server.DataPath + " ...")
+	if err := os.Rename(previousDataPath, server.DataPath); err != nil {
+		return err
+	}
+	// Remove the directory of the previous location, if no other server is left there
+	_ = os.Remove(previousServersPath)
+	return nil
+}
+
+// getPostgresServer returns the shared PostgreSQL server
+func getPostgresServer() *databaseServer {
+	return &databaseServer{
+		Driver:        beachsandbox.DatabaseDriverPostgres,
+		Image:         config.Current.Postgres.Image,
+		ServiceName:   "postgres",
+		ContainerName: postgresContainerName,
+		DataPath:      path.Postgres,
+		Port:          5432,
+	}
+}
+
+// getProjectDatabaseServer returns the shared database server for the given project configuration
+func getProjectDatabaseServer(projectConfig *beachsandbox.Config) *databaseServer {
+	if projectConfig.DatabaseDriver == beachsandbox.DatabaseDriverPostgres {
+		return getPostgresServer()
+	}
+	return getDatabaseServer(projectConfig.DatabaseImage)
+}
+
+// getSandboxDatabaseServer returns the shared database server for the given sandbox
+func getSandboxDatabaseServer(sandbox *beachsandbox.BeachSandbox) *databaseServer {
+	return getProjectDatabaseServer(sandbox.Config)
+}
+
+// getAdditionalDatabaseServers returns the additional database servers used by the registered projects
+func getAdditionalDatabaseServers() []*databaseServer {
+	projectRegistry, err := registry.Load(getProjectRegistryPathAndFilename())
+	if err != nil {
+		log.Debug("Failed loading project registry: ", err)
+		return nil
+	}
+
+	var servers []*databaseServer
+	knownServers := map[string]bool{}
+	for _, project := range projectRegistry.Projects {
+		projectConfig, err := beachsandbox.LoadConfig(project.RootPath)
+		if err != nil {
+			continue
+		}
+		server := getProjectDatabaseServer(projectConfig)
+		if server.IsDefault() || knownServers[server.ServiceName] {
+			continue
+		}
+		knownServers[server.ServiceName] = true
+		servers = append(servers, server)
+	}
+	return servers
+}
+
+// getDatabaseServiceNames returns the compose service names of the default database server and of all
+// additional database servers which have been created already
+func getDatabaseServiceNames() []string {
+	serviceNames := []string{"database"}
+	for _, server := range getAdditionalDatabaseServers() {
+		_, err := getContainerClient().Inspect(context.Background(), server.ContainerName)
+		if err != nil {
+			continue
+		}
+		serviceNames = append(serviceNames, server.ServiceName)
+	}
+	return serviceNames
+}
+
+// IsDefault returns true if this is the default database server
+func (server *databaseServer) IsDefault() bool {
+	return server.ContainerName == databaseContainerName
+}
+
+// Host returns the host name of the server within the Local Beach network
+func (server *databaseServer) Host() string {
+	return server.ContainerName + ".local_beach"
+}
+
+// PublishedPort returns the port the server is published on at 127.0.0.1, or 0 if it is not published
+func (server *databaseServer) PublishedPort() int {
+	if server.IsDefault() {
+		return config.Current.Ports.Database
+	}
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		return config.Current.Ports.Postgres
+	}
+	return 0
+}
+
+// superuser returns the name and password of the administrative user of the server
+func (server *databaseServer) superuser() (string, string) {
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		return "postgres", config.Current.Postgres.Password
+	}
+	return "root", config.Current.Database.RootPassword
+}
+
+// ensureIsRunning returns an error if the database server container is not running
+func (server *databaseServer) ensureIsRunning() error {
+	running, err := isContainerRunning(server.ContainerName)
+	if err != nil {
+		return err
+	}
+	if !running {
+		return errors.New("the database server " + server.ContainerName + " is not running, start it with \"beach start\"")
+	}
+	return nil
+}
+
+// clientCommand returns the arguments for running the given client in the server container. MariaDB 11 dropped
+// the mysql* commands, MySQL has no mariadb* commands, so whichever exists is used.
+func (server *databaseServer) clientCommand(mariadbCommand string, mysqlCommand string, args ...string) []string {
+	commandArgs := []string{server.ContainerName, "sh", "-c", `exec "$(command -v ` + mariadbCommand + ` || command -v ` + mysqlCommand + `)" "$@"`, mysqlCommand, "-u", "root", "--password=" + config.Current.Database.RootPassword}
+	return append(commandArgs, args...)
+}
+
+// postgresClientCommand returns the arguments for running the given PostgreSQL client in the server container.
+// Connections through the local socket are trusted, so no password is needed.
+func (server *databaseServer) postgresClientCommand(command string, args ...string) []string {
+	return append([]string{server.ContainerName, command, "--username=postgres"}, args...)
+}
+
+// consoleExecOptions returns the options for running the command line client of the server in its container,
+// connected to the given database as the given user, with the given additional client arguments
+func (server *databaseServer) consoleExecOptions(username string, password string, databaseName string, args ...string) container.ExecOptions {
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		return container.ExecOptions{
+			Command: append([]string{"psql", "--username=" + username, "--dbname=" + databaseName}, args...),
+			Env:     []string{"PGPASSWORD=" + password},
+		}
+	}
+
+	// The password is passed through the environment, so that the client does not warn about it
+	command := []string{"sh", "-c", `exec "$(command -v mariadb || command -v mysql)" "$@"`, "mysql", "--user=" + username}
+	return container.ExecOptions{
+		Command: append(append(command, args...), databaseName),
+		Env:     []string{"MYSQL_PWD=" + password},
+	}
+}
+
+// executeStatement runs the given SQL statements as root, or as postgres for PostgreSQL. Columns of the result are
+// separated by tabs.
+func (server *databaseServer) executeStatement(statements ...string) (string, error) {
+	var commandArgs []string
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		// Each statement is passed separately, because some, like CREATE DATABASE, must not run in a transaction
+		commandArgs = append([]string{"exec"}, server.postgresClientCommand("psql", "--set=ON_ERROR_STOP=1", "--no-align", "--tuples-only", "--field-separator=\t")...)
+		for _, statement := range statements {
+			commandArgs = append(commandArgs, "--command", statement)
+		}
+	} else {
+		commandArgs = append([]string{"exec"}, server.clientCommand("mariadb", "mysql", "--batch", "--skip-column-names", "--execute", strings.Join(statements, "; "))...)
+	}
+	output, err := exec.RunCommand(getContainerRuntime().Command, commandArgs)
+	if err != nil {
+		return output, errors.New("failed executing database statement: " + strings.TrimSpace(output))
+	}
+	return output, nil
+}
+
+// createDatabase creates the given database, if it does not exist yet
+func (server *databaseServer) createDatabase(databaseName string) error {
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		// PostgreSQL does not support CREATE DATABASE IF NOT EXISTS
+		output, err := server.executeStatement("SELECT 1 FROM pg_database WHERE datname = '" + databaseName + "'")
+		if err != nil || strings.TrimSpace(output) == "1" {
+			return err
+		}
+		_, err = server.executeStatement(`CREATE DATABASE "` + databaseName + `"`)
+		return err
+	}
+
+	_, err := server.executeStatement("CREATE DATABASE IF NOT EXISTS `" + databaseName + "` " + databaseCharacterSetOptions)
+	return err
+}
+
+// createDatabaseUser creates the given user, if it does not exist yet, sets its password and grants it all
+// privileges on the given database, and only on that one
+func (server *databaseServer) createDatabaseUser(username string, password string, databaseName string) error {
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		// The owner of a database may create tables in its public schema, PUBLIC may connect to any database by default
+		_, err := server.executeStatement(
+			"DO $$ BEGIN IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = '"+username+"') THEN CREATE ROLE \""+username+"\"; END IF; END $$",
+			"ALTER ROLE \""+username+"\" WITH LOGIN PASSWORD '"+password+"'",
+			"ALTER DATABASE \""+databaseName+"\" OWNER TO \""+username+"\"",
+			"REVOKE CONNECT ON DATABASE \""+databaseName+"\" FROM PUBLIC",
+		)
+		return err
+	}
+
+	user := "'" + username + "'@'%'"
+	_, err := server.executeStatement("CREATE USER IF NOT EXISTS " + user + " IDENTIFIED BY '" + password + "'; " +
+		"ALTER USER " + user + " IDENTIFIED BY '" + password + "'; " +
+		"GRANT ALL PRIVILEGES ON `" + databaseName + "`.* TO " + user)
+	return err
+}
+
+// recreateDatabase drops the given database, if it exists, and creates a new, empty one
+func (server *databaseServer) recreateDatabase(databaseName string) error {
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		// The owner is kept, so that the project user can still access the new database
+		output, err := server.executeStatement("SELECT pg_catalog.pg_get_userbyid(datdba) FROM pg_database WHERE datname = '" + databaseName + "'")
+		if err != nil {
+			return err
+		}
+		statements := []string{`DROP DATABASE IF EXISTS "` + databaseName + `" WITH (FORCE)`, `CREATE DATABASE "` + databaseName + `"`}
+		if owner := strings.TrimSpace(output); len(owner) > 0 && owner != "postgres" {
+			statements = append(statements, `ALTER DATABASE "`+databaseName+`" OWNER TO "`+owner+`"`, `REVOKE CONNECT ON DATABASE "`+databaseName+`" FROM PUBLIC`)
+		}
+		_, err = server.executeStatement(statements...)
+		return err
+	}
+
+	_, err := server.executeStatement("DROP DATABASE IF EXISTS `"+databaseName+"`", "CREATE DATABASE `"+databaseName+"` "+databaseCharacterSetOptions)
+	return err
+}
+
+// dropDatabase drops the given database, if it exists
+func (server *databaseServer) dropDatabase(databaseName string) error {
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		_, err := server.executeStatement(`DROP DATABASE IF EXISTS "` + databaseName + `" WITH (FORCE)`)
+		return err
+	}
+
+	_, err := server.executeStatement("DROP DATABASE IF EXISTS `" + databaseName + "`")
+	return err
+}
+
+// dropDatabaseUser drops the given user, if it exists
+func (server *databaseServer) dropDatabaseUser(username string) error {
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		_, err := server.executeStatement(`DROP ROLE IF EXISTS "` + username + `"`)
+		return err
+	}
+
+	_, err := server.executeStatement("DROP USER IF EXISTS '" + username + "'@'%'")
+	return err
+}
+
+// exportDatabase writes an SQL dump of the given database to destination
+func (server *databaseServer) exportDatabase(databaseName string, destination io.Writer) error {
+	commandArgs := append([]string{"exec"}, server.clientCommand("mariadb-dump", "mysqldump", "--single-transaction", "--routines", "--triggers", databaseName)...)
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		commandArgs = append([]string{"exec"}, server.postgresClientCommand("pg_dump", databaseName)...)
+	}
+	return exec.RunPipedCommand(getContainerRuntime().Command, commandArgs, nil, destination)
+}
+
+// exportPortableDatabase writes a dump of the given MySQL database to destination which can be imported by MySQL and
+// by users without the SUPER privilege: DEFINER clauses are skipped and the MariaDB sandbox mode line is removed.
+func (server *databaseServer) exportPortableDatabase(databaseName string, destination io.Writer) error {
+	// Only newer clients support --skip-definer, the filter removes the clauses written by older ones
+	script := `dump="$(command -v mariadb-dump || command -v mysqldump)" && ` +
+		`if "$dump" --help | grep -q -- --skip-definer; then set -- --skip-definer "$@"; fi && exec "$dump" "$@"`
+	commandArgs := []string{"exec", server.ContainerName, "sh", "-c", script, "mysqldump", "-u", "root", "--password=" + config.Current.Database.RootPassword, "--single-transaction", "--routines", "--triggers", databaseName}
+
+	filter := &portableDumpWriter{destination: destination}
+	if err := exec.RunPipedCommand(getContainerRuntime().Command, commandArgs, nil, filter); err != nil {
+		return err
+	}
+	return filter.Flush()
+}
+
+// portableDumpWriter removes the lines and clauses from a MariaDB dump which MySQL, or a user without the SUPER
+// privilege, cannot import. Data is passed on line by line, call Flush to write an incomplete last line.
+type portableDumpWriter struct {
+	destination io.Writer
+	buffer      []byte
+	lineCount   int
+}
+
+// portableDumpDefinerPattern matches DEFINER clauses of views, routines, triggers and events
+var portableDumpDefinerPattern = regexp.MustCompile("DEFINER=(`[^`]*`|'[^']*'|[^ @]*)@(`[^`]*`|'[^']*'|[^ */]*) ?")
+
+func (writer *portableDumpWriter) Write(data []byte) (int, error) {
+	writer.buffer = append(writer.buffer, data...)
+	for {
+		end := bytes.IndexByte(writer.buffer, '\n')
+		if end == -1 {
+			return len(data), nil
+		}
+		if err := writer.writeLine(writer.buffer[:end+1]); err != nil {
+			return 0, err
+		}
+		writer.buffer = writer.buffer[end+1:]
+	}
+}
+
+func (writer *portableDumpWriter) Flush() error {
+	if len(writer.buffer) == 0 {
+		return nil
+	}
+	err := writer.writeLine(writer.buffer)
+	writer.buffer = nil
+	return err
+}
+
+func (writer *portableDumpWriter) writeLine(line []byte) error {
+	writer.lineCount++
+	// MariaDB 11.4 and later start dumps with "/*M!999999\- enable the sandbox mode */", which MySQL cannot parse
+	if writer.lineCount == 1 && bytes.HasPrefix(line, []byte("/*M!999999")) {
+		return nil
+	}
+	// Data may contain the word as well, so only statements which can have a DEFINER are changed
+	if !bytes.HasPrefix(line, []byte("INSERT ")) && bytes.Contains(line, []byte("DEFINER=")) {
+		line = portableDumpDefinerPattern.ReplaceAll(line, nil)
+	}
+	_, err := writer.destination.Write(line)
+	return err
+}
+
+// importDatabase recreates the given database and imports the SQL read from source. The SQL is read into a temporary
+// file first, so that a source which cannot be read completely, like a truncated gzip file, leaves the database alone.
+func (server *databaseServer) importDatabase(databaseName string, source io.Reader) error {
+	file, err := os.CreateTemp("", "localbeach-db-import-*.sql")
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}(file)
+
+	if _, err := io.Copy(file, source); err != nil {
+		return errors.New("failed reading the dump, the database was not changed: " + err.Error())
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if err := server.recreateDatabase(databaseName); err != nil {
+		return err
+	}
+
+	commandArgs := append([]string{"exec", "-i"}, server.clientCommand("mariadb", "mysql", databaseName)...)
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		commandArgs = append([]string{"exec", "-i"}, server.postgresClientCommand("psql", "--set=ON_ERROR_STOP=1", "--quiet", databaseName)...)
+	}
+	return exec.RunPipedCommand(getContainerRuntime().Command, commandArgs, file, nil)
+}
+
+// prepareSandboxDatabase creates the database of the given sandbox and its dedicated user on the given server,
+// if they do not exist yet
+func prepareSandboxDatabase(sandbox *beachsandbox.BeachSandbox, server *databaseServer) error {
+	if err := validateDatabaseDriver(sandbox.Config.DatabaseDriver); err != nil {
+		return err
+	}
+
+	log.Debug("Creating project database (if needed) ...")
+	err := server.createDatabase(sandbox.ProjectName)
+	if err != nil {
+		return err
+	}
+
+	username, password, err := getSandboxDatabaseCredentials(sandbox)
+	if err != nil {
+		return err
+	}
+	if username == "root" || username == "postgres" {
+		log.Debug("Project uses the database superuser, not creating a dedicated user")
+		return nil
+	}
+
+	log.Debug("Creating project database user " + username + " (if needed) ...")
+	return server.createDatabaseUser(username, password, sandbox.ProjectName)
+}
+
+// validateDatabaseDriver returns an error if the given driver is not supported
+func validateDatabaseDriver(driver string) error {
+	switch driver {
+	case beachsandbox.DatabaseDriverMySQL, beachsandbox.DatabaseDriverPostgres:
+		return nil
+	}
+	return errors.New("BEACH_DATABASE_DRIVER must be " + beachsandbox.DatabaseDriverMySQL + " or " + beachsandbox.DatabaseDriverPostgres + ", \"" + driver + "\" given")
+}
+
+// getSandboxDatabaseLogin returns the name and password of the user the given sandbox connects to its database
+// with, which is the superuser of the server if the sandbox has no dedicated user yet
+func getSandboxDatabaseLogin(sandbox *beachsandbox.BeachSandbox, server *databaseServer) (string, string) {
+	username := sandbox.Config.Get("BEACH_DATABASE_USERNAME")
+	password := sandbox.Config.Get("BEACH_DATABASE_PASSWORD")
+	if len(username) == 0 || len(password) == 0 {
+		return server.superuser()
+	}
+	return username, password
+}
+
+// getSandboxDatabaseCredentials returns the credentials of the database user of the given sandbox. Sandboxes
+// created before Local Beach used dedicated users have none, so credentials are generated and stored in
+// .localbeach.env, and the sandbox configuration is reloaded.
+func getSandboxDatabaseCredentials(sandbox *beachsandbox.BeachSandbox) (username string, password string, err error) {
+	username = sandbox.Config.Get("BEACH_DATABASE_USERNAME")
+	password = sandbox.Config.Get("BEACH_DATABASE_PASSWORD")
+	if username == "root" {
+		return username, password, nil
+	}
+	if len(username) > 0 && len(password) > 0 {
+		if databaseUsernameInvalidCharacters.MatchString(username) || strings.Contains(password, "'") {
+			return "", "", errors.New("BEACH_DATABASE_USERNAME may only contain letters, digits and underscores, and BEACH_DATABASE_PASSWORD must not contain single quotes")
+		}
+		return username, password, nil
+	}
+
+	if len(username) == 0 {
+		username = getDatabaseUsername(sandbox.ProjectName)
+	}
+	if len(password) == 0 {
+		randomBytes := make([]byte, 16)
+		if _, err := rand.Read(randomBytes); err != nil {
+			return "", "", errors.New("failed generating database password: " + err.Error())
+		}
+		password = hex.EncodeToString(randomBytes)
+	}
+
+	pathAndFilename := filepath.Join(sandbox.ProjectRootPath, ".localbeach.env")
+	if err := beachsandbox.SetConfigValue(pathAndFilename, "BEACH_DATABASE_USERNAME", username); err != nil {
+		return "", "", err
+	}
+	if err := beachsandbox.SetConfigValue(pathAndFilename, "BEACH_DATABASE_PASSWORD", password); err != nil {
+		return "", "", err
+	}
+	log.Info("Created credentials for the project database user " + username + " in .localbeach.env")
+
+	sandbox.Config, err = beachsandbox.LoadConfig(sandbox.ProjectRootPath)
+	if err != nil {
+		return "", "", err
+	}
+	return username, password, nil
+}
+
+// getDatabaseUsername derives the name of the database user from the given project name. Truncating and replacing
+// characters could map different project names to the same user, so a hash of the project name is appended.
+func getDatabaseUsername(projectName string) string {
+	hash := sha256.Sum256([]byte(projectName))
+	suffix := "_" + hex.EncodeToString(hash[:])[:8]
+
+	prefix := databaseUsernameInvalidCharacters.ReplaceAllString(projectName, "_")
+	if len(prefix) > databaseUsernameMaximumLength-len(suffix) {
+		prefix = prefix[:databaseUsernameMaximumLength-len(suffix)]
+	}
+	return prefix + suffix
+}
+
+// databaseServerProblem is a known cause for the database server failing to start, recognized by its log output
+type databaseServerProblem struct {
+	Patterns  []string
+	Diagnosis string
+	Fix       string
+}
+
+// databaseServerProblems returns the known problems, the patterns are matched case-insensitively against the
+// log output of the database server
+func databaseServerProblems(server *databaseServer) []databaseServerProblem {
+	return []databaseServerProblem{
+		{
+			Patterns:  []string{"permission denied", "errno: 13", "error number 13", "can't create/write to file"},
+			Diagnosis: "The database server is not allowed to access its data directory " + server.DataPath + ".",
+			Fix:       "Check the ownership and permissions with \"ls -ln '" + server.DataPath + "'\" and make the directory and all its files writable for the database server, which runs as user 999 in the container.",
+		},
+		{
+			Patterns:  []string{"upgrade after a crash is not supported", "unsupported redo log format", "needs upgrade", "mariadb-upgrade", "mysql_upgrade", "was created with mariadb", "was created with mysql"},
+			Diagnosis: "The data in " + server.DataPath + " was written by a different version of the database server, which is " + server.Image + " now, and cannot be upgraded automatically.",
+			Fix:       "Start the previous image once and stop it cleanly with \"beach down\" before upgrading, or export all databases with the previous version, move " + server.DataPath + " aside and import them again.",
+		},
+		{
+			Patterns:  []string{"page corruption", "is corrupted", "corrupt", "tablespace is missing", "innodb_force_recovery"},
+			Diagnosis: "The InnoDB data in " + server.DataPath + " seems to be corrupt, possibly because the database server was not stopped cleanly.",
+			Fix:       "Restore a snapshot with \"beach db:snapshot restore\" after moving " + server.DataPath + " aside, or try to recover the data by adding --innodb-force-recovery=1 to the server command.",
+		},
+	}
+}
+
+// waitForDatabaseServer waits until the given database server is healthy, showing relevant log lines written since
+// the given time. If the server stops or the configured timeout passes, the returned error contains a diagnosis.
+func waitForDatabaseServer(server *databaseServer, since time.Time) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logWriter := &databaseLogWriter{}
+	go func() {
+		_ = getContainerClient().Logs(ctx, server.ContainerName, container.LogsOptions{Since: since, Follow: true}, logWriter, logWriter)
+	}()
+
+	timeout := config.Current.Database.StartupTimeout
+	deadline := time.Now().Add(timeout)
+	for {
+		containerDetails, err := getContainerClient().Inspect(context.Background(), server.ContainerName)
+		if err != nil {
+			return errors.New("failed to check for database server container health: " + err.Error())
+		}
+		if containerDetails.Health == container.HealthHealthy {
+			return nil
+		}
+		if containerDetails.State == "exited" || containerDetails.State == "dead" {
+			return newDatabaseServerError(server, "the database server stopped unexpectedly", since)
+		}
+		if time.Now().After(deadline) {
+			return newDatabaseServerError(server, "timeout waiting for database server to start after "+timeout.String(), since)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// newDatabaseServerError returns an error with the given message and a diagnosis based on the log output of the
+// database server since the given time
+func newDatabaseServerError(server *databaseServer, message string, since time.Time) error {
+	var output bytes.Buffer
+	_ = getContainerClient().Logs(context.Background(), server.ContainerName, container.LogsOptions{Since: since}, &output, &output)
+
+	logOutput := strings.ToLower(output.String())
+	for _, problem := range databaseServerProblems(server) {
+		for _, pattern := range problem.Patterns {
+			if strings.Contains(logOutput, pattern) {
+				return errors.New(message + "\n" + problem.Diagnosis + "\n" + problem.Fix)
+			}
+		}
+	}
+	printLastContainerLogs(server.ContainerName)
+	return errors.New(message + ", check the complete log with \"" + getContainerRuntime().Command + " logs " + server.ContainerName + "\". If the server is just slow, increase database.startupTimeout in the global configuration.")
+}
+
+// databaseLogWriter shows those log lines of the database server which are relevant for diagnosing startup problems
+type databaseLogWriter struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (writer *databaseLogWriter) Write(data []byte) (int, error) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	writer.buffer.Write(data)
+	for {
+		line, err := writer.buffer.ReadString('\n')
+		if err != nil {
+			// Keep the incomplete line until the rest of it arrives
+			writer.buffer.Reset()
+			writer.buffer.WriteString(line)
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if isRelevantDatabaseLogLine(line) {
+			log.Info("database: " + line)
+		}
+	}
+	return len(data), nil
+}
+
+func isRelevantDatabaseLogLine(line string) bool {
+	for _, keyword := range []string{"[ERROR]", "[Entrypoint]", "ready for connections", "upgrade", "Upgrade", "corrupt", "Permission denied"} {
+		if strings.Contains(line, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetDatabaseUsername(t *testing.T) {
+	tests := []struct {
+		name        string
+		projectName string
+		prefix      string
+	}{
+		{name: "simple project name", projectName: "acme", prefix: "acme_"},
+		{name: "invalid characters", projectName: "acme-website.de", prefix: "acme_website_de_"},
+		{name: "long project name", projectName: "acme_corporate_website_relaunch_2026", prefix: "acme_corporate_website_"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			username := getDatabaseUsername(test.projectName)
+			if !strings.HasPrefix(username, test.prefix) {
+				t.Errorf("expected %q to start with %q", username, test.prefix)
+			}
+			if len(username) > databaseUsernameMaximumLength {
+				t.Errorf("expected at most %d characters, got %q", databaseUsernameMaximumLength, username)
+			}
+			if databaseUsernameInvalidCharacters.MatchString(username) {
+				t.Errorf("expected only valid characters, got %q", username)
+			}
+			if username != getDatabaseUsername(test.projectName) {
+				t.Errorf("expected the same user name for the same project name")
+			}
+		})
+	}
+
+	collidingProjectNames := [][2]string{
+		{"acme_corporate_website_relaunch_2026", "acme_corporate_website_relaunch_2027"},
+		{"acme-website", "acme_website"},
+	}
+	for _, projectNames := range collidingProjectNames {
+		if getDatabaseUsername(projectNames[0]) == getDatabaseUsername(projectNames[1]) {
+			t.Errorf("expected different user names for %q and %q", projectNames[0], projectNames[1])
+		}
+	}
+}
+
+func TestPortableDumpWriter(t *testing.T) {
+	dump := "/*M!999999\\- enable the sandbox mode */ \n" +
+		"-- MariaDB dump 10.19  Distrib 11.4.5-MariaDB\n" +
+		"/*!50013 DEFINER=`root`@`localhost` SQL SECURITY DEFINER */\n" +
+		"CREATE DEFINER=`root`@`%` PROCEDURE `cleanup`()\n" +
+		"/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`localhost`*/ /*!50003 TRIGGER `t` BEFORE INSERT ON `a` FOR EACH ROW SET NEW.b = 1 */;;\n" +
+		"INSERT INTO `a` VALUES ('DEFINER=`root`@`localhost` ');\n" +
+		"-- Dump completed"
+	expected := "-- MariaDB dump 10.19  Distrib 11.4.5-MariaDB\n" +
+		"/*!50013 SQL SECURITY DEFINER */\n" +
+		"CREATE PROCEDURE `cleanup`()\n" +
+		"/*!50003 CREATE*/ /*!50017 */ /*!50003 TRIGGER `t` BEFORE INSERT ON `a` FOR EACH ROW SET NEW.b = 1 */;;\n" +
+		"INSERT INTO `a` VALUES ('DEFINER=`root`@`localhost` ');\n" +
+		"-- Dump completed"
+
+	output := new(strings.Builder)
+	writer := &portableDumpWriter{destination: output}
+	// Lines are split across writes, like they are when read from a pipe
+	for _, chunk := range strings.SplitAfter(dump, "`") {
+		if _, err := writer.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if output.String() != expected {
+		t.Errorf("unexpected dump:\n%v", output.String())
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var databaseExportCompress bool
+
+// databaseExportCmd represents the db:export command
+var databaseExportCmd = &cobra.Command{
+	Use:   "db:export [file]",
+	Short: "Export the database of the Local Beach instance in the current directory",
+	Long: `db:export
+
+This command exports the database of the Local Beach instance in the current
+directory as an SQL dump. If no file is given (or "-"), the dump is written to
+stdout.
+
+The dump is compressed with gzip if --gzip is given or the filename ends
+with ".gz".
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  handleDatabaseExportRun,
+}
+
+func init() {
+	databaseExportCmd.Flags().BoolVarP(&databaseExportCompress, "gzip", "z", false, "Compress the dump with gzip")
+	rootCmd.AddCommand(databaseExportCmd)
+}
+
+func handleDatabaseExportRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	server := getSandboxDatabaseServer(sandbox)
+	err = server.ensureIsRunning()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	if len(args) == 0 || args[0] == "-" {
+		err = writeDatabaseDump(server, sandbox.ProjectName, os.Stdout, databaseExportCompress)
+		if err != nil {
+			log.Fatal("Failed exporting database: ", err)
+		}
+		return
+	}
+
+	filename := args[0]
+	if strings.HasSuffix(filename, ".gz") {
+		databaseExportCompress = true
+	}
+	log.Info("Exporting database " + sandbox.ProjectName + " to " + filename + " ...")
+	err = exportDatabaseToFile(server, sandbox.ProjectName, filename, databaseExportCompress)
+	if err != nil {
+		log.Fatal("Failed exporting database: ", err)
+		return
+	}
+	log.Info("Done")
+	return
+}
+
+// exportDatabaseToFile writes a dump of the given database to the given file, which is removed if the export fails
+func exportDatabaseToFile(server *databaseServer, databaseName string, pathAndFilename string, compress bool) error {
+	file, err := os.Create(pathAndFilename)
+	if err != nil {
+		return err
+	}
+
+	err = writeDatabaseDump(server, databaseName, file, compress)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(pathAndFilename)
+	}
+	return err
+}
+
+// writeDatabaseDump writes a dump of the given database to destination, compressed with gzip if compress is set
+func writeDatabaseDump(server *databaseServer, databaseName string, destination io.Writer, compress bool) error {
+	if !compress {
+		return server.exportDatabase(databaseName, destination)
+	}
+
+	gzipWriter := gzip.NewWriter(destination)
+	// The gzip writer is not closed on failure, so that a truncated dump never looks complete
+	if err := server.exportDatabase(databaseName, gzipWriter); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
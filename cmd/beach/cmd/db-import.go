@@ -0,0 +1,103 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// databaseImportCmd represents the db:import command
+var databaseImportCmd = &cobra.Command{
+	Use:   "db:import [file]",
+	Short: "Import an SQL dump into the database of the Local Beach instance in the current directory",
+	Long: `db:import
+
+This command imports an SQL dump into the database of the Local Beach instance
+in the current directory. If no file is given (or "-"), the dump is read from
+stdin. Gzip-compressed dumps are detected automatically.
+
+Be aware that the existing database is dropped and created again before the
+dump is imported.
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  handleDatabaseImportRun,
+}
+
+func init() {
+	rootCmd.AddCommand(databaseImportCmd)
+}
+
+func handleDatabaseImportRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	server := getSandboxDatabaseServer(sandbox)
+	err = server.ensureIsRunning()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var source io.Reader = os.Stdin
+	if len(args) > 0 && args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		defer func(file *os.File) {
+			_ = file.Close()
+		}(file)
+		source = file
+	}
+
+	source, err = decompressIfNeeded(source)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	log.Info("Importing database " + sandbox.ProjectName + " ...")
+	err = server.importDatabase(sandbox.ProjectName, source)
+	if err != nil {
+		log.Fatal("Failed importing database: ", err)
+		return
+	}
+	log.Info("Done")
+	return
+}
+
+// decompressIfNeeded returns a reader which transparently decompresses the given source if it is gzip-compressed
+func decompressIfNeeded(source io.Reader) (io.Reader, error) {
+	bufferedSource := bufio.NewReader(source)
+	magicBytes, err := bufferedSource.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(magicBytes) == 2 && magicBytes[0] == 0x1f && magicBytes[1] == 0x8b {
+		return gzip.NewReader(bufferedSource)
+	}
+	return bufferedSource, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var databaseInfoOutputFormat string
+
+// databaseInformation contains the connection details of a project database. PublishedHost and PublishedPort
+// are used by clients running on the host, like GUI tools, Host and Port by containers in the Local Beach network.
+type databaseInformation struct {
+	Driver        string `json:"driver" yaml:"driver"`
+	Server        string `json:"server" yaml:"server"`
+	Image         string `json:"image" yaml:"image"`
+	Database      string `json:"database" yaml:"database"`
+	Username      string `json:"username" yaml:"username"`
+	Password      string `json:"password" yaml:"password"`
+	Host          string `json:"host" yaml:"host"`
+	Port          int    `json:"port" yaml:"port"`
+	PublishedHost string `json:"publishedHost,omitempty" yaml:"publishedHost,omitempty"`
+	PublishedPort int    `json:"publishedPort,omitempty" yaml:"publishedPort,omitempty"`
+}
+
+// databaseInfoCmd represents the db:info command
+var databaseInfoCmd = &cobra.Command{
+	Use:   "db:info",
+	Short: "Show the connection details of the database of the Local Beach instance in the current directory",
+	Long: `db:info
+
+This command shows the connection details of the database of the Local Beach
+instance in the current directory. Use the published host and port for
+connecting with a client running on your computer, like a GUI tool.
+`,
+	Args: cobra.ExactArgs(0),
+	Run:  handleDatabaseInfoRun,
+}
+
+func init() {
+	addOutputFlag(databaseInfoCmd, &databaseInfoOutputFormat)
+	rootCmd.AddCommand(databaseInfoCmd)
+}
+
+func handleDatabaseInfoRun(cmd *cobra.Command, args []string) {
+	if err := validateOutputFormat(databaseInfoOutputFormat); err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	server := getSandboxDatabaseServer(sandbox)
+	username, password := getSandboxDatabaseLogin(sandbox, server)
+	information := databaseInformation{
+		Driver:   server.Driver,
+		Server:   server.ContainerName,
+		Image:    server.Image,
+		Database: sandbox.ProjectName,
+		Username: username,
+		Password: password,
+		Host:     server.Host(),
+		Port:     server.Port,
+	}
+	if publishedPort := server.PublishedPort(); publishedPort > 0 {
+		information.PublishedHost = "127.0.0.1"
+		information.PublishedPort = publishedPort
+	}
+
+	if handled, err := printStructuredOutput(databaseInfoOutputFormat, information); handled || err != nil {
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	published := "not published"
+	if information.PublishedPort > 0 {
+		published = information.PublishedHost + ":" + strconv.Itoa(information.PublishedPort)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintf(writer, "Driver:\t%v\n", information.Driver)
+	_, _ = fmt.Fprintf(writer, "Server:\t%v (%v)\n", information.Server, information.Image)
+	_, _ = fmt.Fprintf(writer, "Database:\t%v\n", information.Database)
+	_, _ = fmt.Fprintf(writer, "Username:\t%v\n", information.Username)
+	_, _ = fmt.Fprintf(writer, "Password:\t%v\n", information.Password)
+	_, _ = fmt.Fprintf(writer, "Host and port on this computer:\t%v\n", published)
+	_, _ = fmt.Fprintf(writer, "Host and port in containers:\t%v:%d\n", information.Host, information.Port)
+	_ = writer.Flush()
+}
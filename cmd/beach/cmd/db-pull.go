@@ -0,0 +1,138 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// databasePullCmd represents the db:pull command
+var databasePullCmd = &cobra.Command{
+	Use:   "db:pull",
+	Short: "Download the database of a Beach instance into the Local Beach instance in the current directory",
+	Long: `db:pull
+
+This command downloads a dump of the database of a Beach instance and imports
+it into the database of the Local Beach instance in the current directory.
+
+The connection is established via SSH through the jump host of the given
+cluster, just like resource-download does. Combine both commands for a full
+copy of a Beach instance.
+
+Be aware that the existing local database is dropped and created again before
+the dump is imported. The dump is downloaded completely before the local
+database is touched.
+
+Notes:
+ - older Beach instances may use a namespace called "beach"
+`,
+	Args: cobra.ExactArgs(0),
+	Run:  handleDatabasePullRun,
+}
+
+func init() {
+	databasePullCmd.Flags().StringVar(&instanceIdentifier, "instance", "", "instance identifier of the Beach instance to download from, eg. 'instance-123abc45-def6-7890-abcd-1234567890ab'")
+	databasePullCmd.Flags().StringVar(&projectNamespace, "namespace", "", "The project namespace of the Beach instance to download from, eg. 'beach-project-123abc45-def6-7890-abcd-1234567890ab'")
+	databasePullCmd.Flags().StringVar(&clusterIdentifier, "cluster", "", "The cluster identifier of the Beach instance to download from, eg. 'h9acc4'")
+	_ = databasePullCmd.MarkFlagRequired("instance")
+	_ = databasePullCmd.MarkFlagRequired("namespace")
+	rootCmd.AddCommand(databasePullCmd)
+}
+
+func handleDatabasePullRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	server := getSandboxDatabaseServer(sandbox)
+	if server.Driver != beachsandbox.DatabaseDriverMySQL {
+		log.Fatal("Beach instances use MySQL, so PostgreSQL databases cannot be pulled from them")
+		return
+	}
+	err = server.ensureIsRunning()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	log.Info(fmt.Sprintf("Downloading database of instance %v ...", getInstanceInternalHost(instanceIdentifier, projectNamespace)))
+	dumpPathAndFilename, err := downloadDatabaseDump()
+	if err != nil {
+		log.Fatal("Failed downloading database: ", err)
+		return
+	}
+
+	log.Info("Importing database " + sandbox.ProjectName + " ...")
+	err = importCompressedDatabaseDump(server, sandbox.ProjectName, dumpPathAndFilename)
+	_ = os.Remove(dumpPathAndFilename)
+	if err != nil {
+		log.Fatal("Failed importing database: ", err)
+		return
+	}
+	log.Info("Done")
+	return
+}
+
+// downloadDatabaseDump writes a gzip-compressed dump of the database of the Beach instance into a temporary file.
+// The caller is responsible for removing the file, which is already removed if an error is returned.
+func downloadDatabaseDump() (string, error) {
+	file, err := os.CreateTemp("", "localbeach-db-pull-*.sql.gz")
+	if err != nil {
+		return "", err
+	}
+
+	script := "mysqldump " + remoteDatabaseConnectionOptions + ` --single-transaction --routines --triggers --no-tablespaces "$BEACH_DATABASE_NAME" | gzip`
+	err = runInstanceScript(instanceIdentifier, projectNamespace, clusterIdentifier, script, nil, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = checkCompressedFile(file.Name())
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+
+	if info, err := os.Stat(file.Name()); err == nil {
+		log.Debug("Downloaded " + formatByteSize(info.Size()))
+	}
+	return file.Name(), nil
+}
+
+// importCompressedDatabaseDump imports the given gzip-compressed dump into the given database
+func importCompressedDatabaseDump(server *databaseServer, databaseName string, dumpPathAndFilename string) error {
+	file, err := os.Open(dumpPathAndFilename)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	source, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed reading dump: %v", err)
+	}
+	return server.importDatabase(databaseName, source)
+}
@@ -0,0 +1,252 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var databasePushDryRun bool
+
+// databasePushCmd represents the db:push command
+var databasePushCmd = &cobra.Command{
+	Use:   "db:push",
+	Short: "Upload the database of the Local Beach instance in the current directory to a Beach instance",
+	Long: `db:push
+
+This command replaces the database of a Beach instance with a dump of the
+database of the Local Beach instance in the current directory.
+
+Before anything is changed, you need to confirm the target by typing the
+instance identifier. The local database is then exported into a temporary
+file and checked, a backup of the remote database is stored in the home
+directory of the Beach instance, the local dump is uploaded and, only after
+the upload succeeded, all tables and views of the remote database are dropped
+and the dump is imported. If the import fails, the backup is imported again.
+
+The dump contains no DEFINER clauses and no MariaDB specific sandbox mode
+line, so that it can be imported by MySQL and without the SUPER privilege.
+
+Use --dry-run to only display the tables and sizes of both databases.
+
+Notes:
+ - older Beach instances may use a namespace called "beach"
+`,
+	Args: cobra.ExactArgs(0),
+	Run:  handleDatabasePushRun,
+}
+
+func init() {
+	databasePushCmd.Flags().StringVar(&instanceIdentifier, "instance", "", "instance identifier of the Beach instance to upload to, eg. 'instance-123abc45-def6-7890-abcd-1234567890ab'")
+	databasePushCmd.Flags().StringVar(&projectNamespace, "namespace", "", "The project namespace of the Beach instance to upload to, eg. 'beach-project-123abc45-def6-7890-abcd-1234567890ab'")
+	databasePushCmd.Flags().StringVar(&clusterIdentifier, "cluster", "", "The cluster identifier of the Beach instance to upload to, eg. 'h9acc4'")
+	databasePushCmd.Flags().BoolVar(&databasePushDryRun, "dry-run", false, "Only display table counts and sizes, don't change anything")
+	_ = databasePushCmd.MarkFlagRequired("instance")
+	_ = databasePushCmd.MarkFlagRequired("namespace")
+	rootCmd.AddCommand(databasePushCmd)
+}
+
+func handleDatabasePushRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	server := getSandboxDatabaseServer(sandbox)
+	if server.Driver != beachsandbox.DatabaseDriverMySQL {
+		log.Fatal("Beach instances use MySQL, so PostgreSQL databases cannot be pushed to them")
+		return
+	}
+	err = server.ensureIsRunning()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	if databasePushDryRun {
+		err = displayDatabasePushSummary(server, sandbox.ProjectName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	fmt.Printf("This will REPLACE the database of the following Beach instance with the local database %v:\n\n", sandbox.ProjectName)
+	fmt.Printf("  Instance:  %v\n", instanceIdentifier)
+	fmt.Printf("  Namespace: %v\n", projectNamespace)
+	if clusterIdentifier != "" {
+		fmt.Printf("  Cluster:   %v\n", clusterIdentifier)
+	}
+	fmt.Println()
+	if askForInput("Type the instance identifier to confirm: ") != instanceIdentifier {
+		log.Fatal("Aborted, the database was not changed")
+		return
+	}
+
+	log.Info("Exporting local database " + sandbox.ProjectName + " ...")
+	dumpPathAndFilename, err := createTemporaryDatabaseDump(server, sandbox.ProjectName)
+	if err != nil {
+		log.Fatal("Failed exporting local database, the remote database was not changed: ", err)
+		return
+	}
+
+	err = pushDatabaseDump(dumpPathAndFilename, sandbox.ProjectName)
+	_ = os.Remove(dumpPathAndFilename)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	log.Info("Done")
+	return
+}
+
+// createTemporaryDatabaseDump writes a portable, gzip-compressed dump of the given database into a temporary file
+// and checks that it is complete. The caller is responsible for removing the file.
+func createTemporaryDatabaseDump(server *databaseServer, databaseName string) (string, error) {
+	file, err := os.CreateTemp("", "localbeach-db-push-*.sql.gz")
+	if err != nil {
+		return "", err
+	}
+
+	gzipWriter := gzip.NewWriter(file)
+	err = server.exportPortableDatabase(databaseName, gzipWriter)
+	if err == nil {
+		err = gzipWriter.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = checkCompressedFile(file.Name())
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// checkCompressedFile returns an error if the given file is not a complete gzip stream
+func checkCompressedFile(pathAndFilename string) error {
+	file, err := os.Open(pathAndFilename)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("%v is not a valid gzip file: %v", pathAndFilename, err)
+	}
+	if _, err := io.Copy(io.Discard, gzipReader); err != nil {
+		return fmt.Errorf("%v is not a valid gzip file: %v", pathAndFilename, err)
+	}
+	return nil
+}
+
+// pushDatabaseDump creates a backup of the remote database, uploads the given dump and, only after the upload
+// succeeded, replaces all tables and views of the remote database with it. If the import fails, the backup is
+// imported again.
+func pushDatabaseDump(dumpPathAndFilename string, databaseName string) error {
+	backupFilename := "localbeach-backup-" + time.Now().Format("20060102-150405") + ".sql.gz"
+	log.Info("Creating backup of remote database as ~/" + backupFilename + " ...")
+	script := "mysqldump " + remoteDatabaseConnectionOptions + ` --single-transaction --routines --triggers --no-tablespaces "$BEACH_DATABASE_NAME" | gzip > "$HOME/` + backupFilename + `"`
+	err := runInstanceScript(instanceIdentifier, projectNamespace, clusterIdentifier, script, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed creating backup of remote database, the database was not changed: %v", err)
+	}
+
+	source, err := os.Open(dumpPathAndFilename)
+	if err != nil {
+		return err
+	}
+	defer func(source *os.File) {
+		_ = source.Close()
+	}(source)
+
+	log.Info(fmt.Sprintf("Uploading database %v to instance %v ...", databaseName, getInstanceInternalHost(instanceIdentifier, projectNamespace)))
+	// Views are dropped first, because they may depend on tables
+	dropQuery := strings.ReplaceAll("SELECT CONCAT('DROP ', IF(table_type = 'VIEW', 'VIEW', 'TABLE'), ' IF EXISTS `', table_name, '`;') FROM information_schema.tables WHERE table_schema = DATABASE() ORDER BY table_type = 'VIEW' DESC", "`", "\\`")
+	dropTables := `{ echo "SET FOREIGN_KEY_CHECKS=0;"; mysql ` + remoteDatabaseConnectionOptions + ` --batch --skip-column-names --execute "` + dropQuery + `" "$BEACH_DATABASE_NAME"; } | mysql ` + remoteDatabaseConnectionOptions + ` "$BEACH_DATABASE_NAME"`
+	importDump := func(dumpPath string) string {
+		return `gunzip < ` + dumpPath + ` | mysql ` + remoteDatabaseConnectionOptions + ` "$BEACH_DATABASE_NAME"`
+	}
+	script = `dump=$(mktemp) && trap 'rm -f "$dump"' EXIT && cat > "$dump" && gzip -t "$dump" && gzip -t "$HOME/` + backupFilename + `" && ` +
+		dropTables + ` && ` +
+		`if ! ` + importDump(`"$dump"`) + `; then ` +
+		`echo "Import failed, restoring the backup ..." >&2; ` + dropTables + ` && ` + importDump(`"$HOME/`+backupFilename+`"`) + ` && echo "The backup was restored" >&2; exit 1; fi`
+
+	err = runInstanceScript(instanceIdentifier, projectNamespace, clusterIdentifier, script, source, nil)
+	if err != nil {
+		return fmt.Errorf("failed pushing database, a backup is available at ~/%v in the instance: %v", backupFilename, err)
+	}
+	return nil
+}
+
+func displayDatabasePushSummary(server *databaseServer, databaseName string) error {
+	output, err := server.executeStatement("SELECT table_name, table_rows, data_length + index_length FROM information_schema.tables WHERE table_schema = '" + databaseName + "' ORDER BY table_name")
+	if err != nil {
+		return err
+	}
+
+	var tableCount, totalSize int64
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "TABLE\tROWS (APPROX.)\tSIZE")
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		columns := strings.Split(line, "\t")
+		if len(columns) != 3 {
+			continue
+		}
+		size, _ := strconv.ParseInt(columns[2], 10, 64)
+		tableCount++
+		totalSize += size
+		_, _ = fmt.Fprintf(writer, "%v\t%v\t%v\n", columns[0], columns[1], formatByteSize(size))
+	}
+	_ = writer.Flush()
+
+	remoteOutput := new(bytes.Buffer)
+	script := "mysql " + remoteDatabaseConnectionOptions + ` --batch --skip-column-names --execute "SELECT COUNT(*), COALESCE(SUM(data_length + index_length), 0) FROM information_schema.tables WHERE table_schema = DATABASE()" "$BEACH_DATABASE_NAME"`
+	err = runInstanceScript(instanceIdentifier, projectNamespace, clusterIdentifier, script, nil, remoteOutput)
+	if err != nil {
+		return fmt.Errorf("failed retrieving database information from instance: %v", err)
+	}
+	remoteColumns := strings.Split(strings.TrimSpace(remoteOutput.String()), "\t")
+	if len(remoteColumns) != 2 {
+		return fmt.Errorf("unexpected database information received from instance: %v", remoteOutput.String())
+	}
+	remoteSize, _ := strconv.ParseInt(remoteColumns[1], 10, 64)
+
+	fmt.Println()
+	fmt.Printf("Local database %v: %v tables, %v\n", databaseName, tableCount, formatByteSize(totalSize))
+	fmt.Printf("Remote database of %v: %v tables, %v\n", getInstanceInternalHost(instanceIdentifier, projectNamespace), remoteColumns[0], formatByteSize(remoteSize))
+	fmt.Println()
+	fmt.Println("Dry run, nothing was changed.")
+	return nil
+}
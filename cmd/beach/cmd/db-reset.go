@@ -0,0 +1,157 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/container"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var databaseResetMigrate bool
+var databaseResetSeed bool
+var databaseResetYes bool
+
+// databaseResetCmd represents the db:reset command
+var databaseResetCmd = &cobra.Command{
+	Use:   "db:reset",
+	Short: "Drop and recreate the database of the Local Beach instance in the current directory",
+	Long: `db:reset
+
+This command drops the database of the Local Beach instance in the current
+directory and creates a new, empty one. The databases of other projects are
+not touched.
+
+Before anything is changed, you need to confirm by typing the project name,
+unless --yes is given.
+
+With --migrate, "./flow doctrine:migrate" is run afterwards in the PHP
+container. With --seed, the commands defined in BEACH_DATABASE_SEED_COMMANDS
+are run in the PHP container as well, separated by semicolons, for example:
+
+  BEACH_DATABASE_SEED_COMMANDS="./flow user:create admin password Admin User; ./flow site:import Vendor.Site"
+`,
+	Args: cobra.ExactArgs(0),
+	Run:  handleDatabaseResetRun,
+}
+
+func init() {
+	databaseResetCmd.Flags().BoolVar(&databaseResetMigrate, "migrate", false, "Run the Doctrine migrations after recreating the database")
+	databaseResetCmd.Flags().BoolVar(&databaseResetSeed, "seed", false, "Run the commands defined in BEACH_DATABASE_SEED_COMMANDS after recreating the database")
+	databaseResetCmd.Flags().BoolVarP(&databaseResetYes, "yes", "y", false, "Don't ask for confirmation")
+	rootCmd.AddCommand(databaseResetCmd)
+}
+
+func handleDatabaseResetRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	server := getSandboxDatabaseServer(sandbox)
+	err = server.ensureIsRunning()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var commands []string
+	if databaseResetMigrate {
+		commands = append(commands, "./flow doctrine:migrate")
+	}
+	if databaseResetSeed {
+		seedCommands := getDatabaseSeedCommands(sandbox)
+		if len(seedCommands) == 0 {
+			log.Fatal("There are no seed commands, define them in BEACH_DATABASE_SEED_COMMANDS")
+			return
+		}
+		commands = append(commands, seedCommands...)
+	}
+
+	containerName := sandbox.ProjectName + "_php"
+	if len(commands) > 0 {
+		phpIsRunning, err := isContainerRunning(containerName)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		if !phpIsRunning {
+			log.Fatal("The container " + containerName + " is not running, start the project with \"beach start\"")
+			return
+		}
+	}
+
+	if !databaseResetYes {
+		log.Warn("This will DELETE all data in the database " + sandbox.ProjectName + " on " + server.ContainerName)
+		if askForInput("Type the project name to confirm: ") != sandbox.ProjectName {
+			log.Fatal("Aborted, the database was not changed")
+			return
+		}
+	}
+
+	log.Info("Recreating database " + sandbox.ProjectName + " ...")
+	err = server.recreateDatabase(sandbox.ProjectName)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	for _, command := range commands {
+		log.Info("Running " + command + " ...")
+		err = runFlowCommand(sandbox, command)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+	}
+	log.Info("Done")
+}
+
+// getDatabaseSeedCommands returns the seed commands of the given sandbox, which are separated by semicolons in
+// BEACH_DATABASE_SEED_COMMANDS
+func getDatabaseSeedCommands(sandbox *beachsandbox.BeachSandbox) []string {
+	var commands []string
+	for _, command := range strings.Split(sandbox.Config.Get("BEACH_DATABASE_SEED_COMMANDS"), ";") {
+		if command = strings.TrimSpace(command); len(command) > 0 {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// runFlowCommand runs the given shell command in the Flow root path of the PHP container of the given sandbox
+func runFlowCommand(sandbox *beachsandbox.BeachSandbox, command string) error {
+	exitCode, err := getContainerClient().Exec(context.Background(), sandbox.ProjectName+"_php", container.ExecOptions{
+		Command:    []string{"bash", "-l", "-c", command},
+		WorkingDir: strings.TrimSuffix("/application/"+sandbox.FlowRootPath, "/"),
+		Stdout:     os.Stdout,
+		Stderr:     os.Stderr,
+	})
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return errors.New("\"" + command + "\" failed with exit code " + strconv.Itoa(exitCode))
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var databaseShellExecute string
+
+// databaseShellCmd represents the db:shell command
+var databaseShellCmd = &cobra.Command{
+	Use:     "db:shell",
+	Aliases: []string{"db:console"},
+	Short:   "Open an SQL console for the database of the Local Beach instance in the current directory",
+	Long: `db:shell
+
+This command opens the command line client of the database server, connected
+to the database of the Local Beach instance in the current directory, with the
+same user as the application.
+
+SQL can also be piped into the client, for example:
+
+  beach db:shell < fixtures.sql
+
+or be passed with --execute:
+
+  beach db:shell -e "SHOW TABLES"
+`,
+	Args: cobra.ExactArgs(0),
+	Run:  handleDatabaseShellRun,
+}
+
+func init() {
+	databaseShellCmd.Flags().StringVarP(&databaseShellExecute, "execute", "e", "", "Execute the given statement and quit")
+	rootCmd.AddCommand(databaseShellCmd)
+}
+
+func handleDatabaseShellRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	server := getSandboxDatabaseServer(sandbox)
+	err = server.ensureIsRunning()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var clientArgs []string
+	if len(databaseShellExecute) > 0 {
+		if server.Driver == beachsandbox.DatabaseDriverPostgres {
+			clientArgs = []string{"--command", databaseShellExecute}
+		} else {
+			clientArgs = []string{"--execute", databaseShellExecute}
+		}
+	}
+
+	username, password := getSandboxDatabaseLogin(sandbox, server)
+	execOptions := server.consoleExecOptions(username, password, sandbox.ProjectName, clientArgs...)
+	execOptions.TTY = isTTY()
+	execOptions.Stdout = os.Stdout
+	execOptions.Stderr = os.Stderr
+	// Note: stdin is attached if it is not a TTY as well, so that SQL can be piped into the client, but not
+	// if a statement is given, because the client would not read it anyway
+	if execOptions.TTY || len(databaseShellExecute) == 0 {
+		execOptions.Stdin = os.Stdin
+	}
+
+	exitCode, err := getContainerClient().Exec(context.Background(), server.ContainerName, execOptions)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/exec"
+	"github.com/flownative/localbeach/pkg/path"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var snapshotForce bool
+
+var snapshotNameFilter = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// databaseSnapshot contains the metadata stored next to each snapshot dump
+type databaseSnapshot struct {
+	Name          string    `json:"name"`
+	ProjectName   string    `json:"projectName"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Size          int64     `json:"size"`
+	GitCommit     string    `json:"gitCommit,omitempty"`
+	ServerVersion string    `json:"serverVersion,omitempty"`
+}
+
+// databaseSnapshotCmd represents the db:snapshot command
+var databaseSnapshotCmd = &cobra.Command{
+	Use:   "db:snapshot",
+	Short: "Manage named snapshots of the database of the Local Beach instance in the current directory",
+	Long: `db:snapshot
+
+Snapshots are compressed dumps of the project database, which are stored
+per project in the Local Beach base directory. Create one before running
+risky migrations and restore it if something goes wrong.
+`,
+}
+
+var databaseSnapshotCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a snapshot of the project database",
+	Args:  cobra.ExactArgs(1),
+	Run:   handleDatabaseSnapshotCreateRun,
+}
+
+var databaseSnapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the snapshots of the project database",
+	Args:  cobra.ExactArgs(0),
+	Run:   handleDatabaseSnapshotListRun,
+}
+
+var databaseSnapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Replace the project database with the given snapshot",
+	Args:  cobra.ExactArgs(1),
+	Run:   handleDatabaseSnapshotRestoreRun,
+}
+
+var databaseSnapshotDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete the given snapshot",
+	Args:  cobra.ExactArgs(1),
+	Run:   handleDatabaseSnapshotDeleteRun,
+}
+
+func init() {
+	databaseSnapshotCreateCmd.Flags().BoolVarP(&snapshotForce, "force", "f", false, "Overwrite an existing snapshot with the same name")
+	databaseSnapshotCmd.AddCommand(databaseSnapshotCreateCmd)
+	databaseSnapshotCmd.AddCommand(databaseSnapshotListCmd)
+	databaseSnapshotCmd.AddCommand(databaseSnapshotRestoreCmd)
+	databaseSnapshotCmd.AddCommand(databaseSnapshotDeleteCmd)
+	rootCmd.AddCommand(databaseSnapshotCmd)
+}
+
+func handleDatabaseSnapshotCreateRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	name := args[0]
+	dumpPathAndFilename, metadataPathAndFilename, err := getSnapshotPathAndFilenames(sandbox.ProjectName, name)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if _, err := os.Stat(metadataPathAndFilename); err == nil && !snapshotForce {
+		log.Fatal(fmt.Sprintf("A snapshot named %v already exists, use --force to overwrite it", name))
+		return
+	}
+
+	server := getSandboxDatabaseServer(sandbox)
+	err = server.ensureIsRunning()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	snapshot := databaseSnapshot{
+		Name:        name,
+		ProjectName: sandbox.ProjectName,
+		CreatedAt:   time.Now(),
+	}
+
+	output, err := server.executeStatement("SELECT VERSION()")
+	if err == nil {
+		snapshot.ServerVersion = strings.TrimSpace(output)
+	}
+	output, err = exec.RunCommand("git", []string{"-C", sandbox.ProjectRootPath, "rev-parse", "--short", "HEAD"})
+	if err == nil {
+		snapshot.GitCommit = strings.TrimSpace(output)
+	}
+
+	log.Info(fmt.Sprintf("Creating snapshot %v of database %v ...", name, sandbox.ProjectName))
+	ensureDirectoryForFileExists(dumpPathAndFilename)
+	temporaryPathAndFilename := dumpPathAndFilename + ".tmp"
+	err = exportDatabaseToFile(server, sandbox.ProjectName, temporaryPathAndFilename, true)
+	if err != nil {
+		log.Fatal("Failed creating snapshot: ", err)
+		return
+	}
+	err = os.Rename(temporaryPathAndFilename, dumpPathAndFilename)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	info, err := os.Stat(dumpPathAndFilename)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	snapshot.Size = info.Size()
+
+	metadata, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	err = os.WriteFile(metadataPathAndFilename, metadata, 0644)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	log.Info(fmt.Sprintf("Created snapshot %v (%v)", name, formatByteSize(snapshot.Size)))
+	return
+}
+
+func handleDatabaseSnapshotListRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	snapshots, err := getDatabaseSnapshots(sandbox.ProjectName)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if len(snapshots) == 0 {
+		log.Info("There are no snapshots for project " + sandbox.ProjectName)
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "NAME\tCREATED\tSIZE\tGIT COMMIT\tSERVER VERSION")
+	for _, snapshot := range snapshots {
+		_, _ = fmt.Fprintf(writer, "%v\t%v\t%v\t%v\t%v\n", snapshot.Name, snapshot.CreatedAt.Format("2006-01-02 15:04:05"), formatByteSize(snapshot.Size), snapshot.GitCommit, snapshot.ServerVersion)
+	}
+	_ = writer.Flush()
+	return
+}
+
+func handleDatabaseSnapshotRestoreRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	name := args[0]
+	dumpPathAndFilename, _, err := getSnapshotPathAndFilenames(sandbox.ProjectName, name)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	file, err := os.Open(dumpPathAndFilename)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Fatal(fmt.Sprintf("A snapshot named %v does not exist", name))
+		return
+	} else if err != nil {
+		log.Fatal(err)
+		return
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	server := getSandboxDatabaseServer(sandbox)
+	err = server.ensureIsRunning()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	source, err := gzip.NewReader(file)
+	if err != nil {
+		log.Fatal("Failed reading snapshot: ", err)
+		return
+	}
+
+	log.Info(fmt.Sprintf("Restoring snapshot %v into database %v ...", name, sandbox.ProjectName))
+	err = server.importDatabase(sandbox.ProjectName, source)
+	if err != nil {
+		log.Fatal("Failed restoring snapshot: ", err)
+		return
+	}
+	log.Info("Done")
+	return
+}
+
+func handleDatabaseSnapshotDeleteRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	name := args[0]
+	dumpPathAndFilename, metadataPathAndFilename, err := getSnapshotPathAndFilenames(sandbox.ProjectName, name)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if _, err := os.Stat(metadataPathAndFilename); errors.Is(err, os.ErrNotExist) {
+		log.Fatal(fmt.Sprintf("A snapshot named %v does not exist", name))
+		return
+	}
+
+	for _, pathAndFilename := range []string{dumpPathAndFilename, metadataPathAndFilename} {
+		err = os.Remove(pathAndFilename)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Fatal(err)
+			return
+		}
+	}
+	log.Info("Deleted snapshot " + name)
+	return
+}
+
+// getSnapshotPathAndFilenames returns the paths of the files of the given snapshot, or an error if the name is
+// invalid or the files would not be located in the snapshot directory of the project
+func getSnapshotPathAndFilenames(projectName string, name string) (dumpPathAndFilename string, metadataPathAndFilename string, err error) {
+	if !snapshotNameFilter.MatchString(name) || strings.Trim(name, ".") == "" {
+		return "", "", errors.New("the snapshot name may only contain letters, digits, dots, dashes and underscores")
+	}
+
+	snapshotsPath := filepath.Join(path.Snapshots, projectName)
+	basePathAndFilename := filepath.Join(snapshotsPath, name)
+	if filepath.Dir(basePathAndFilename) != filepath.Clean(snapshotsPath) || filepath.Dir(snapshotsPath) != filepath.Clean(path.Snapshots) {
+		return "", "", errors.New("invalid snapshot name " + name)
+	}
+	return basePathAndFilename + ".sql.gz", basePathAndFilename + ".json", nil
+}
+
+func getDatabaseSnapshots(projectName string) ([]databaseSnapshot, error) {
+	metadataPathAndFilenames, err := filepath.Glob(filepath.Join(path.Snapshots, projectName, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []databaseSnapshot
+	for _, metadataPathAndFilename := range metadataPathAndFilenames {
+		metadata, err := os.ReadFile(metadataPathAndFilename)
+		if err != nil {
+			return nil, err
+		}
+		var snapshot databaseSnapshot
+		if err := json.Unmarshal(metadata, &snapshot); err != nil {
+			return nil, errors.New("failed parsing snapshot metadata in " + metadataPathAndFilename + ": " + err.Error())
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
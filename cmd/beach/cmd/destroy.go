@@ -0,0 +1,238 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/certificates"
+	"github.com/flownative/localbeach/pkg/exec"
+	"github.com/flownative/localbeach/pkg/path"
+	"github.com/flownative/localbeach/pkg/registry"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var destroyDeleteResources bool
+var destroyDeleteFiles bool
+var destroyYes bool
+var destroySkipDatabase bool
+
+// destroyCmd represents the destroy command
+var destroyCmd = &cobra.Command{
+	Use:   "destroy",
+	Short: "Remove the Local Beach instance in the current directory completely",
+	Long: `destroy
+
+This command removes the Local Beach instance in the current directory: its
+containers and volumes, its database and database user, the certificates
+issued for its hosts, and its entry in the list of known projects.
+
+With --delete-resources, the persistent resources in Data/Persistent/Resources
+are deleted as well, and with --delete-files, the .localbeach.env file with the
+local settings and database credentials. The committed .localbeach.dist.env and
+.localbeach.docker-compose.yaml files are kept.
+
+The database server needs to be running, so that the database and database
+user can be dropped. Use --skip-database to remove the project anyway and keep
+its database and database user.
+
+A summary of everything that will be deleted is shown first, and you need to
+confirm by typing the project name, unless --yes is given.
+`,
+	Args: cobra.ExactArgs(0),
+	Run:  handleDestroyRun,
+}
+
+func init() {
+	destroyCmd.Flags().BoolVar(&destroyDeleteResources, "delete-resources", false, "Delete the persistent resources in Data/Persistent/Resources")
+	destroyCmd.Flags().BoolVar(&destroyDeleteFiles, "delete-files", false, "Delete the .localbeach.env file of the project")
+	destroyCmd.Flags().BoolVar(&destroySkipDatabase, "skip-database", false, "Keep the database and database user, for example if the database server cannot be started")
+	destroyCmd.Flags().BoolVarP(&destroyYes, "yes", "y", false, "Don't ask for confirmation")
+	rootCmd.AddCommand(destroyCmd)
+}
+
+func handleDestroyRun(cmd *cobra.Command, args []string) {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil && !errors.Is(err, beachsandbox.ErrNoFlowFound) {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
+
+	server := getSandboxDatabaseServer(sandbox)
+	serverIsRunning, err := isContainerRunning(server.ContainerName)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if !serverIsRunning && !destroySkipDatabase {
+		log.Fatal("The database server " + server.ContainerName + " is not running, so the database cannot be dropped. Run \"beach start\" first, or use --skip-database to keep the database and database user.")
+		return
+	}
+	dropDatabase := serverIsRunning && !destroySkipDatabase
+	username, _ := getSandboxDatabaseLogin(sandbox, server)
+	superuser, _ := server.superuser()
+
+	certificatePathAndFilenames, err := getProjectCertificateFiles(sandbox)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	var projectFiles []string
+	if destroyDeleteFiles {
+		// Only the local settings are deleted, .localbeach.dist.env and the Docker Compose file are usually committed
+		pathAndFilename := filepath.Join(sandbox.ProjectRootPath, ".localbeach.env")
+		if _, err := os.Stat(pathAndFilename); err == nil {
+			projectFiles = append(projectFiles, pathAndFilename)
+		}
+	}
+
+	fmt.Printf("This will DELETE the following of the project %v in %v:\n\n", sandbox.ProjectName, sandbox.ProjectRootPath)
+	fmt.Println("  - all containers and volumes")
+	if dropDatabase {
+		fmt.Printf("  - the database %v on %v\n", sandbox.ProjectName, server.ContainerName)
+		if username != superuser {
+			fmt.Printf("  - the database user %v on %v\n", username, server.ContainerName)
+		}
+	}
+	for _, pathAndFilename := range certificatePathAndFilenames {
+		fmt.Printf("  - %v\n", pathAndFilename)
+	}
+	if destroyDeleteResources {
+		fmt.Printf("  - %v\n", sandbox.ProjectDataPersistentResourcesPath)
+	}
+	for _, pathAndFilename := range projectFiles {
+		fmt.Printf("  - %v\n", pathAndFilename)
+	}
+	fmt.Println()
+	if !dropDatabase {
+		log.Warn("The database " + sandbox.ProjectName + " and its user are kept on " + server.ContainerName + ", because --skip-database was given")
+	}
+
+	if !destroyYes {
+		if askForInput("Type the project name to confirm: ") != sandbox.ProjectName {
+			log.Fatal("Aborted, nothing was deleted")
+			return
+		}
+	}
+
+	log.Info("Removing containers and volumes ...")
+	commandArgs := []string{"compose", "-f", sandbox.DockerComposeFilePath, "down", "--remove-orphans", "--volumes"}
+	err = exec.RunInteractiveCommandWithEnv(getContainerRuntime().Command, commandArgs, getSandboxEnvironment(sandbox))
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	if dropDatabase {
+		log.Info("Dropping database " + sandbox.ProjectName + " ...")
+		err = server.dropDatabase(sandbox.ProjectName)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		if username != superuser {
+			err = server.dropDatabaseUser(username)
+			if err != nil {
+				log.Fatal(err)
+				return
+			}
+		}
+	}
+
+	if len(certificatePathAndFilenames) > 0 {
+		log.Info("Removing certificates ...")
+		for _, pathAndFilename := range certificatePathAndFilenames {
+			err = os.Remove(pathAndFilename)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Fatal(err)
+				return
+			}
+		}
+		err = reloadReverseProxy()
+		if err != nil {
+			log.Warn("Failed reloading reverse proxy: ", err)
+		}
+	}
+
+	if destroyDeleteResources {
+		log.Info("Deleting persistent resources ...")
+		err = os.RemoveAll(sandbox.ProjectDataPersistentResourcesPath)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+	}
+
+	for _, pathAndFilename := range projectFiles {
+		err = os.Remove(pathAndFilename)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+	}
+
+	projectRegistry, err := registry.Load(getProjectRegistryPathAndFilename())
+	if err == nil {
+		projectRegistry.Unregister(sandbox.ProjectRootPath)
+		err = projectRegistry.Save()
+	}
+	if err != nil {
+		log.Warn("Failed updating project registry: ", err)
+	}
+
+	log.Info("The project " + sandbox.ProjectName + " was removed")
+}
+
+// getProjectCertificateFiles returns the paths of the certificate and key files issued for the hosts of the given
+// sandbox. Certificates which are also used by other registered projects, like wildcard certificates, are skipped.
+func getProjectCertificateFiles(sandbox *beachsandbox.BeachSandbox) ([]string, error) {
+	sharedNames := map[string]bool{"default": true}
+	projectRegistry, err := registry.Load(getProjectRegistryPathAndFilename())
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projectRegistry.Projects {
+		if project.RootPath == sandbox.ProjectRootPath {
+			continue
+		}
+		if projectConfig, err := beachsandbox.LoadConfig(project.RootPath); err == nil {
+			for _, host := range projectConfig.VirtualHosts {
+				sharedNames[certificates.FilenameForHost(host)] = true
+			}
+		}
+	}
+
+	var pathAndFilenames []string
+	for _, host := range sandbox.Config.VirtualHosts {
+		name := certificates.FilenameForHost(host)
+		if sharedNames[name] {
+			continue
+		}
+		sharedNames[name] = true
+		for _, extension := range []string{".crt", ".key"} {
+			pathAndFilename := filepath.Join(path.Certificates, name+extension)
+			if _, err := os.Stat(pathAndFilename); err == nil {
+				pathAndFilenames = append(pathAndFilenames, pathAndFilename)
+			}
+		}
+	}
+	return pathAndFilenames, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/flownative/localbeach/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const dnsContainerName = "local_beach_dns"
+
+// dnsCmd represents the dns command
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Manage the DNS resolver for the Local Beach domain",
+	Long: `dns
+
+Local Beach can run a small DNS server, which resolves the configured domain
+and all its subdomains to 127.0.0.1 and forwards all other queries. Enable it
+in ~/.LocalBeach/config.yaml:
+
+dns:
+  enabled: true
+`,
+}
+
+var dnsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Verify that the Local Beach domain resolves to this computer",
+	Args:  cobra.ExactArgs(0),
+	Run:   handleDnsStatusRun,
+}
+
+func init() {
+	dnsCmd.AddCommand(dnsStatusCmd)
+	rootCmd.AddCommand(dnsCmd)
+}
+
+func handleDnsStatusRun(cmd *cobra.Command, args []string) {
+	testHost := "hello." + config.Current.Domain
+	failed := false
+
+	if config.Current.DNS.Enabled {
+		log.Info("DNS resolver is enabled for " + config.Current.Domain)
+
+		running, err := isContainerRunning(dnsContainerName)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		if running {
+			log.Info("Container " + dnsContainerName + " is running")
+
+			localResolver := &net.Resolver{
+				PreferGo: true,
+				Dial: func(ctx context.Context, network string, address string) (net.Conn, error) {
+					dialer := net.Dialer{}
+					return dialer.DialContext(ctx, network, net.JoinHostPort("127.0.0.1", strconv.Itoa(config.Current.DNS.Port)))
+				},
+			}
+			if err := checkHostResolvesToLocalhost(localResolver, testHost); err != nil {
+				log.Error("The DNS resolver does not resolve " + testHost + ": " + err.Error())
+				failed = true
+			} else {
+				log.Info("The DNS resolver resolves " + testHost + " to 127.0.0.1")
+			}
+		} else {
+			log.Error("Container " + dnsContainerName + " is not running, start it with \"beach start\"")
+			failed = true
+		}
+	} else {
+		log.Info("DNS resolver is disabled, " + config.Current.Domain + " needs to be resolved by your system's DNS")
+	}
+
+	if err := checkHostResolvesToLocalhost(net.DefaultResolver, testHost); err != nil {
+		log.Error("Your system does not resolve " + testHost + " to 127.0.0.1: " + err.Error())
+		if config.Current.DNS.Enabled {
+			logDnsSetupHint()
+		}
+		failed = true
+	} else {
+		log.Info("Your system resolves " + testHost + " to 127.0.0.1")
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+	return
+}
+
+func checkHostResolvesToLocalhost(resolver *net.Resolver, host string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	addresses, err := resolver.LookupHost(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		if address == "127.0.0.1" || address == "::1" {
+			return nil
+		}
+	}
+	return fmt.Errorf("resolved to %v", addresses)
+}
+
+func logDnsSetupHint() {
+	switch runtime.GOOS {
+	case "darwin":
+		log.Info(fmt.Sprintf("To use the DNS resolver for %v, create the file /etc/resolver/%v with the following content:", config.Current.Domain, config.Current.Domain))
+		log.Info("  nameserver 127.0.0.1")
+		log.Info(fmt.Sprintf("  port %d", config.Current.DNS.Port))
+	default:
+		log.Info(fmt.Sprintf("To use the DNS resolver for %v, configure 127.0.0.1 (port %d) as DNS server for this domain, for example with systemd-resolved or NetworkManager", config.Current.Domain, config.Current.DNS.Port))
+	}
+}
@@ -0,0 +1,458 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/certificates"
+	"github.com/flownative/localbeach/pkg/config"
+	"github.com/flownative/localbeach/pkg/container"
+	"github.com/flownative/localbeach/pkg/exec"
+	"github.com/flownative/localbeach/pkg/path"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	doctorStatusOK      = "ok"
+	doctorStatusSkipped = "skipped"
+	doctorStatusWarning = "warning"
+	doctorStatusFailed  = "failed"
+)
+
+const (
+	doctorMinimumFreeDiskSpace     = 1 << 30
+	doctorRecommendedFreeDiskSpace = 5 << 30
+)
+
+var doctorOutputFormat string
+
+// doctorCheck is a single check run by the doctor command
+type doctorCheck struct {
+	Name string
+	Run  func() doctorCheckResult
+}
+
+// doctorCheckResult is the outcome of a check, Fix describes how to solve a problem
+type doctorCheckResult struct {
+	Name    string `json:"name" yaml:"name"`
+	Status  string `json:"status" yaml:"status"`
+	Message string `json:"message" yaml:"message"`
+	Fix     string `json:"fix,omitempty" yaml:"fix,omitempty"`
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check if this computer is ready for running Local Beach",
+	Long: `Check if this computer is ready for running Local Beach.
+
+The checks cover the container runtime and Docker Compose, the ports used by
+Local Beach, DNS resolution of the project hosts, the certificate authority and
+certificates, the Flow installation of the current project, leftovers of old
+Local Beach versions and free disk space.
+
+Each check reports its result and, if there is a problem, how to fix it. The
+command exits with a non-zero status if any check failed, so it can be used in
+scripts and CI pipelines.`,
+	Args: cobra.ExactArgs(0),
+	Run:  handleDoctorRun,
+}
+
+func init() {
+	addOutputFlag(doctorCmd, &doctorOutputFormat)
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func handleDoctorRun(cmd *cobra.Command, args []string) {
+	if err := validateOutputFormat(doctorOutputFormat); err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	failed := false
+	var results []doctorCheckResult
+	for _, check := range getDoctorChecks() {
+		result := check.Run()
+		result.Name = check.Name
+		if result.Status == doctorStatusFailed {
+			failed = true
+		}
+		results = append(results, result)
+	}
+
+	if handled, err := printStructuredOutput(doctorOutputFormat, results); handled || err != nil {
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		for _, result := range results {
+			switch result.Status {
+			case doctorStatusOK:
+				fmt.Printf("[OK]   %s: %s\n", result.Name, result.Message)
+			case doctorStatusSkipped:
+				fmt.Printf("[SKIP] %s: %s\n", result.Name, result.Message)
+			case doctorStatusWarning:
+				fmt.Printf("[WARN] %s: %s\n", result.Name, result.Message)
+			default:
+				fmt.Printf("[FAIL] %s: %s\n", result.Name, result.Message)
+			}
+			if len(result.Fix) > 0 && result.Status != doctorStatusOK {
+				fmt.Printf("       Fix: %s\n", result.Fix)
+			}
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func getDoctorChecks() []doctorCheck {
+	return []doctorCheck{
+		{Name: "Configuration", Run: checkConfiguration},
+		{Name: "Container runtime", Run: checkContainerRuntime},
+		{Name: "Docker Compose", Run: checkCompose},
+		{Name: "Rootless port binding", Run: checkRootlessPortBinding},
+		{Name: "HTTP port", Run: func() doctorCheckResult {
+			return checkPortIsAvailable(config.Current.Ports.HTTP, "local_beach_nginx", "ports.http")
+		}},
+		{Name: "HTTPS port", Run: func() doctorCheckResult {
+			return checkPortIsAvailable(config.Current.Ports.HTTPS, "local_beach_nginx", "ports.https")
+		}},
+		{Name: "Database port", Run: func() doctorCheckResult {
+			return checkPortIsAvailable(config.Current.Ports.Database, databaseContainerName, "ports.database")
+		}},
+		{Name: "DNS resolution", Run: checkHostResolution},
+		{Name: "Certificate authority", Run: checkCertificateAuthority},
+		{Name: "Certificates", Run: checkCertificates},
+		{Name: "Flow installation", Run: checkFlowInstallation},
+		{Name: "Old Local Beach data", Run: checkOldBase},
+		{Name: "Disk space", Run: checkDiskSpace},
+	}
+}
+
+func checkConfiguration() doctorCheckResult {
+	pathAndFilename := filepath.Join(path.Base, "config.yaml")
+	if configurationError != nil {
+		return doctorCheckResult{
+			Status:  doctorStatusFailed,
+			Message: configurationError.Error() + ", the remaining checks use the default configuration",
+			Fix:     "Correct or remove " + pathAndFilename,
+		}
+	}
+	if _, err := os.Stat(pathAndFilename); errors.Is(err, os.ErrNotExist) {
+		return doctorCheckResult{Status: doctorStatusOK, Message: "using the default configuration"}
+	}
+	return doctorCheckResult{Status: doctorStatusOK, Message: "loaded from " + pathAndFilename}
+}
+
+func checkContainerRuntime() doctorCheckResult {
+	version, err := getContainerRuntime().Version()
+	if err != nil {
+		return doctorCheckResult{
+			Status:  doctorStatusFailed,
+			Message: getContainerRuntime().Name + " is not installed or cannot be run",
+			Fix:     "Install Docker, Podman or nerdctl, or select the installed runtime with runtime.name in the global configuration",
+		}
+	}
+
+	if len(getContainerRuntime().SocketPath) == 0 {
+		fix := "Provide a Docker compatible API socket and configure it with runtime.socketPath in the global configuration"
+		if getContainerRuntime().Name == container.RuntimeNerdctl {
+			fix = "nerdctl provides no API socket, run a Docker compatible API server for containerd and configure its socket with runtime.socketPath in the global configuration"
+		}
+		return doctorCheckResult{
+			Status:  doctorStatusFailed,
+			Message: "using " + version + " without API socket, the reverse proxy cannot detect projects",
+			Fix:     fix,
+		}
+	}
+
+	if _, err := getContainerClient().List(context.Background(), container.ListOptions{}); err != nil {
+		fix := "Start the Docker daemon or Docker Desktop"
+		if getContainerRuntime().Name == container.RuntimePodman {
+			fix = "Enable the Podman API socket with \"systemctl --user enable --now podman.socket\" or start the Podman machine with \"podman machine start\""
+		}
+		return doctorCheckResult{
+			Status:  doctorStatusFailed,
+			Message: "using " + version + ", but cannot connect to " + getContainerRuntime().SocketPath,
+			Fix:     fix,
+		}
+	}
+
+	return doctorCheckResult{
+		Status:  doctorStatusOK,
+		Message: "using " + version + " via " + getContainerRuntime().SocketPath,
+	}
+}
+
+func checkRootlessPortBinding() doctorCheckResult {
+	if runtime.GOOS != "linux" || !getContainerRuntime().IsRootless() {
+		return doctorCheckResult{Status: doctorStatusOK, Message: "not needed, " + getContainerRuntime().Name + " is not running rootless"}
+	}
+
+	lowestPort := config.Current.Ports.HTTP
+	if config.Current.Ports.HTTPS < lowestPort {
+		lowestPort = config.Current.Ports.HTTPS
+	}
+
+	content, err := os.ReadFile("/proc/sys/net/ipv4/ip_unprivileged_port_start")
+	if err != nil {
+		return doctorCheckResult{Status: doctorStatusWarning, Message: "could not determine the lowest unprivileged port: " + err.Error()}
+	}
+	unprivilegedPortStart, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil || unprivilegedPortStart > lowestPort {
+		return doctorCheckResult{
+			Status:  doctorStatusFailed,
+			Message: getContainerRuntime().Name + " is running rootless and cannot publish port " + strconv.Itoa(lowestPort),
+			Fix:     "Run \"sudo sysctl net.ipv4.ip_unprivileged_port_start=" + strconv.Itoa(lowestPort) + "\" or configure ports.http and ports.https above 1023",
+		}
+	}
+	return doctorCheckResult{Status: doctorStatusOK, Message: "ports from " + strconv.Itoa(unprivilegedPortStart) + " can be published without root privileges"}
+}
+
+func checkCompose() doctorCheckResult {
+	output, err := exec.RunCommand(getContainerRuntime().Command, []string{"compose", "version"})
+	if err != nil {
+		fix := "Install the Docker Compose plugin, for example the docker-compose-plugin package, or update Docker Desktop"
+		if getContainerRuntime().Name == container.RuntimePodman {
+			fix = "Install docker-compose or podman-compose, which are used by \"podman compose\""
+		}
+		return doctorCheckResult{
+			Status:  doctorStatusFailed,
+			Message: "\"" + getContainerRuntime().Command + " compose\" is not available",
+			Fix:     fix,
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return doctorCheckResult{Status: doctorStatusOK, Message: strings.TrimSpace(lines[len(lines)-1])}
+}
+
+func checkPortIsAvailable(port int, containerName string, settingName string) doctorCheckResult {
+	portString := strconv.Itoa(port)
+	connection, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", portString), time.Second)
+	if err != nil {
+		return doctorCheckResult{Status: doctorStatusOK, Message: "port " + portString + " is free"}
+	}
+	_ = connection.Close()
+
+	if running, err := isContainerRunning(containerName); err == nil && running {
+		return doctorCheckResult{Status: doctorStatusOK, Message: "port " + portString + " is used by " + containerName}
+	}
+	return doctorCheckResult{
+		Status:  doctorStatusFailed,
+		Message: "port " + portString + " is used by another program",
+		Fix:     "Stop the program using the port (find it with \"sudo lsof -i :" + portString + "\") or use a different port by setting " + settingName + " in the global configuration",
+	}
+}
+
+func checkHostResolution() doctorCheckResult {
+	hosts := []string{"hello." + config.Current.Domain}
+	if sandbox := getDoctorSandbox(); sandbox != nil && len(sandbox.Config.VirtualHosts) > 0 {
+		hosts = sandbox.Config.VirtualHosts
+	}
+
+	var checkedHosts []string
+	for _, host := range hosts {
+		if strings.Contains(host, "*") {
+			continue
+		}
+		if err := checkHostResolvesToLocalhost(net.DefaultResolver, host); err != nil {
+			fix := "Enable the DNS resolver with dns.enabled in the global configuration, or add \"127.0.0.1 " + host + "\" to /etc/hosts"
+			if config.Current.DNS.Enabled {
+				fix = "Make sure that the DNS resolver is running with \"beach start\" and check the setup with \"beach dns status\""
+			}
+			return doctorCheckResult{
+				Status:  doctorStatusFailed,
+				Message: host + " does not resolve to 127.0.0.1: " + err.Error(),
+				Fix:     fix,
+			}
+		}
+		checkedHosts = append(checkedHosts, host)
+	}
+	return doctorCheckResult{Status: doctorStatusOK, Message: strings.Join(checkedHosts, ", ") + " resolve to 127.0.0.1"}
+}
+
+func checkCertificateAuthority() doctorCheckResult {
+	authority, err := loadCertificateAuthority()
+	if errors.Is(err, certificates.ErrNoAuthorityFound) {
+		return doctorCheckResult{
+			Status:  doctorStatusWarning,
+			Message: "there is no certificate authority, so HTTPS is not available",
+			Fix:     "Run \"beach setup-https\"",
+		}
+	} else if err != nil {
+		return doctorCheckResult{Status: doctorStatusFailed, Message: err.Error()}
+	}
+
+	if time.Now().After(authority.Certificate.NotAfter) {
+		return doctorCheckResult{
+			Status:  doctorStatusFailed,
+			Message: "the certificate authority expired on " + authority.Certificate.NotAfter.Format("2006-01-02"),
+			Fix:     "Remove " + filepath.Dir(authority.CertificatePathAndFilename) + " and run \"beach setup-https\"",
+		}
+	}
+	if _, err := authority.Certificate.Verify(x509.VerifyOptions{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+		return doctorCheckResult{
+			Status:  doctorStatusFailed,
+			Message: "the certificate authority is not trusted by this computer",
+			Fix:     "Run \"beach setup-https\" to install it",
+		}
+	}
+	if len(authority.Certificate.PermittedDNSDomains) > 0 {
+		return doctorCheckResult{
+			Status:  doctorStatusWarning,
+			Message: "the certificate authority may only issue certificates for " + strings.Join(authority.Certificate.PermittedDNSDomains, ", ") + ", so projects using other hosts have no valid certificate",
+			Fix:     "Remove " + filepath.Dir(authority.CertificatePathAndFilename) + " and run \"beach setup-https\"",
+		}
+	}
+	return doctorCheckResult{Status: doctorStatusOK, Message: "installed and valid until " + authority.Certificate.NotAfter.Format("2006-01-02")}
+}
+
+func checkCertificates() doctorCheckResult {
+	authority, err := loadCertificateAuthority()
+	if errors.Is(err, certificates.ErrNoAuthorityFound) {
+		return doctorCheckResult{Status: doctorStatusSkipped, Message: "HTTPS is not set up"}
+	} else if err != nil {
+		return doctorCheckResult{Status: doctorStatusFailed, Message: err.Error()}
+	}
+
+	certificateList, err := certificates.List(path.Certificates, authority)
+	if err != nil {
+		return doctorCheckResult{Status: doctorStatusFailed, Message: err.Error()}
+	}
+
+	result := doctorCheckResult{Status: doctorStatusOK}
+	var problems, fixes []string
+	for _, certificate := range certificateList {
+		switch status := getCertificateStatus(certificate); {
+		case status == "expired":
+			result.Status = doctorStatusFailed
+			problems = append(problems, certificate.Name+" expired on "+certificate.NotAfter.Format("2006-01-02"))
+			fixes = append(fixes, "Run \"beach cert renew\"")
+		case status == "expires soon":
+			if result.Status == doctorStatusOK {
+				result.Status = doctorStatusWarning
+			}
+			problems = append(problems, certificate.Name+" expires on "+certificate.NotAfter.Format("2006-01-02"))
+			fixes = append(fixes, "Run \"beach cert renew\"")
+		case !certificate.IssuedByAuthority:
+			if result.Status == doctorStatusOK {
+				result.Status = doctorStatusWarning
+			}
+			problems = append(problems, certificate.Name+" was not issued by the Local Beach certificate authority")
+			fixes = append(fixes, "Run \"beach cert renew --force\"")
+		}
+	}
+
+	if sandbox := getDoctorSandbox(); sandbox != nil {
+		for _, host := range sandbox.Config.VirtualHosts {
+			if !certificates.IsHostCovered(certificateList, host) {
+				if result.Status == doctorStatusOK {
+					result.Status = doctorStatusWarning
+				}
+				problems = append(problems, "there is no certificate for "+host)
+				fixes = append(fixes, "Run \"beach cert issue "+host+"\" or \"beach start\"")
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		result.Message = "all certificates in " + path.Certificates + " are valid"
+		if len(certificateList) == 0 {
+			result.Message = "there are no certificates in " + path.Certificates
+		}
+		return result
+	}
+	result.Message = strings.Join(problems, ", ")
+	result.Fix = strings.Join(removeDuplicates(fixes), ", ")
+	return result
+}
+
+func checkFlowInstallation() doctorCheckResult {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if errors.Is(err, beachsandbox.ErrNoFlowFound) {
+		return doctorCheckResult{
+			Status:  doctorStatusFailed,
+			Message: "there is no flow script in " + filepath.Join(sandbox.ProjectRootPath, sandbox.FlowRootPath),
+			Fix:     "Run \"composer install\", or set BEACH_FLOW_ROOTPATH in .localbeach.dist.env if Flow is installed in a sub directory",
+		}
+	} else if errors.Is(err, beachsandbox.ErrNoLocalBeachConfigurationFound) {
+		return doctorCheckResult{Status: doctorStatusSkipped, Message: "not in a Local Beach project"}
+	} else if err != nil {
+		return doctorCheckResult{
+			Status:  doctorStatusFailed,
+			Message: "the project could not be loaded: " + err.Error(),
+			Fix:     "Check .localbeach.dist.env and .localbeach.env of the project",
+		}
+	}
+	return doctorCheckResult{Status: doctorStatusOK, Message: "found " + filepath.Join(sandbox.ProjectRootPath, sandbox.FlowRootPath, "flow")}
+}
+
+func checkOldBase() doctorCheckResult {
+	if _, err := os.Stat(path.OldBase); err != nil {
+		return doctorCheckResult{Status: doctorStatusOK, Message: "no data of old Local Beach versions found"}
+	}
+	return doctorCheckResult{
+		Status:  doctorStatusWarning,
+		Message: "found data of an old Local Beach version in " + path.OldBase,
+		Fix:     "Run \"beach setup\" to migrate it to " + path.Base,
+	}
+}
+
+func checkDiskSpace() doctorCheckResult {
+	checkedPath := path.Base
+	if _, err := os.Stat(checkedPath); err != nil {
+		checkedPath = filepath.Dir(checkedPath)
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(checkedPath, &stat); err != nil {
+		return doctorCheckResult{Status: doctorStatusWarning, Message: "could not determine free disk space: " + err.Error()}
+	}
+
+	freeSpace := int64(stat.Bavail) * int64(stat.Bsize)
+	message := formatByteSize(freeSpace) + " free in " + checkedPath
+	switch {
+	case freeSpace < doctorMinimumFreeDiskSpace:
+		return doctorCheckResult{Status: doctorStatusFailed, Message: message, Fix: "Free up disk space, for example with \"" + getContainerRuntime().Command + " system prune\""}
+	case freeSpace < doctorRecommendedFreeDiskSpace:
+		return doctorCheckResult{Status: doctorStatusWarning, Message: message, Fix: "Free up disk space, for example with \"" + getContainerRuntime().Command + " system prune\""}
+	}
+	return doctorCheckResult{Status: doctorStatusOK, Message: message}
+}
+
+// getDoctorSandbox returns the active sandbox, as long as its configuration could be loaded, or nil
+func getDoctorSandbox() *beachsandbox.BeachSandbox {
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil && !errors.Is(err, beachsandbox.ErrNoFlowFound) {
+		return nil
+	}
+	return sandbox
+}
@@ -15,11 +15,13 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/flownative/localbeach/pkg/container"
 	"github.com/flownative/localbeach/pkg/path"
 
 	"github.com/flownative/localbeach/pkg/beachsandbox"
@@ -55,7 +57,7 @@ func handleDownRun(cmd *cobra.Command, args []string) {
 			return
 		}
 		commandArgs := []string{"compose", "-f", sandbox.DockerComposeFilePath, "rm", "--force", "--stop", "-v"}
-		output, err := exec.RunCommand("docker", commandArgs)
+		output, err := exec.RunCommandWithEnv(getContainerRuntime().Command, commandArgs, getSandboxEnvironment(sandbox))
 		if err != nil {
 			log.Fatal(output)
 			return
@@ -64,7 +66,7 @@ func handleDownRun(cmd *cobra.Command, args []string) {
 
 	log.Info("Stopping reverse proxy and database server ...")
 	commandArgs := []string{"compose", "-f", filepath.Join(path.Base, "docker-compose.yml"), "rm", "--force", "--stop", "-v"}
-	output, err := exec.RunCommand("docker", commandArgs)
+	output, err := exec.RunCommand(getContainerRuntime().Command, commandArgs)
 	if err != nil {
 		log.Fatal(output)
 		return
@@ -76,21 +78,18 @@ func handleDownRun(cmd *cobra.Command, args []string) {
 func findInstanceRoots() ([]string, error) {
 	var configurationFiles []string
 
-	output, err := exec.RunCommand("docker", []string{"ps", "-q", "--filter", "network=local_beach"})
+	containers, err := getContainerClient().List(context.Background(), container.ListOptions{Network: "local_beach"})
 	if err != nil {
-		return nil, errors.New(output)
+		return nil, err
 	}
-	for _, line := range strings.Split(output, "\n") {
-		containerID := strings.TrimSpace(line)
-		if len(containerID) > 0 {
-			output, err := exec.RunCommand("docker", []string{"inspect", "-f", "{{index .Config.Labels \"com.docker.compose.project.config_files\"}}", containerID})
-			if err != nil {
-				return nil, errors.New(output)
-			}
-			projectDirectory := filepath.Dir(strings.TrimSpace(output))
-			if containsLocalBeachInstance(projectDirectory) {
-				configurationFiles = append(configurationFiles, projectDirectory)
-			}
+	for _, containerDetails := range containers {
+		configFiles := containerDetails.Labels["com.docker.compose.project.config_files"]
+		if len(configFiles) == 0 {
+			continue
+		}
+		projectDirectory := filepath.Dir(strings.Split(configFiles, ",")[0])
+		if containsLocalBeachInstance(projectDirectory) {
+			configurationFiles = append(configurationFiles, projectDirectory)
 		}
 	}
 
@@ -15,12 +15,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/flownative/localbeach/pkg/beachsandbox"
-	"github.com/flownative/localbeach/pkg/exec"
+	"github.com/flownative/localbeach/pkg/container"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -39,6 +40,15 @@ func init() {
 }
 
 func handleExecRun(cmd *cobra.Command, args []string) {
+	// Flag parsing is disabled for this command, so that flags are passed to the container, therefore the
+	// global flags for selecting a project need to be extracted manually
+	args = extractSandboxSelectionArgs(args)
+	err := selectSandbox()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
 	sandbox, err := beachsandbox.GetActiveSandbox()
 	if err != nil {
 		log.Fatal("Could not activate sandbox: ", err)
@@ -48,31 +58,53 @@ func handleExecRun(cmd *cobra.Command, args []string) {
 	// Check if stdin is a TTY (platform-specific implementation in tty_*.go)
 	stdinIsTTY := isTTY()
 
-	// Build Docker exec command with appropriate flags
-	commandArgs := []string{"exec"}
-	if stdinIsTTY {
-		commandArgs = append(commandArgs, "-t", "-i")
-	}
-	// Note: No -i flag when not TTY since stdin isn't connected in RunCommand
-	commandArgs = append(commandArgs, sandbox.ProjectName+"_php")
+	command := []string{"bash"}
 	if len(args) > 0 {
-		commandArgs = append(commandArgs, "bash", "-l", "-c", strings.Trim(fmt.Sprint(args), "[]"))
-	} else {
-		commandArgs = append(commandArgs, "bash")
+		command = []string{"bash", "-l", "-c", strings.Trim(fmt.Sprint(args), "[]")}
 	}
 
-	// Use the appropriate execution method based on TTY detection
+	execOptions := container.ExecOptions{
+		Command: command,
+		TTY:     stdinIsTTY,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+	}
+	// Note: stdin is only attached if it is a TTY, so that the command does not wait for input otherwise
 	if stdinIsTTY {
-		err = exec.RunInteractiveCommand("docker", commandArgs)
-		if err != nil {
-			log.Fatal(err)
-			return
+		execOptions.Stdin = os.Stdin
+	}
+
+	exitCode, err := getContainerClient().Exec(context.Background(), sandbox.ProjectName+"_php", execOptions)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
+
+// extractSandboxSelectionArgs removes leading --project and --root flags from args and applies them
+func extractSandboxSelectionArgs(args []string) []string {
+	for len(args) > 0 {
+		name, value, hasValue := strings.Cut(args[0], "=")
+		if name != "--project" && name != "--root" {
+			break
 		}
-	} else {
-		output, err := exec.RunCommand("docker", commandArgs)
-		fmt.Print(output)
-		if err != nil {
-			os.Exit(1)
+		if !hasValue {
+			if len(args) < 2 {
+				break
+			}
+			value = args[1]
+			args = args[1:]
+		}
+		args = args[1:]
+
+		if name == "--project" {
+			selectedProjectName = value
+		} else {
+			selectedRootPath = value
 		}
 	}
+	return args
 }
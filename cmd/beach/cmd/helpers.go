@@ -15,7 +15,9 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -24,10 +26,15 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/config"
+	"github.com/flownative/localbeach/pkg/container"
 	"github.com/flownative/localbeach/pkg/exec"
+	"github.com/flownative/localbeach/pkg/registry"
 	log "github.com/sirupsen/logrus"
 
 	asset "github.com/flownative/localbeach/assets"
@@ -35,6 +42,35 @@ import (
 
 var instanceIdentifier, projectNamespace, clusterIdentifier string
 
+// containerRuntime is the container runtime selected by the global configuration or detected on this host, use
+// getContainerRuntime for accessing it
+var containerRuntime *container.Runtime
+
+// containerClient is used for inspecting and controlling containers, it can be replaced by a fake in tests. Use
+// getContainerClient for accessing it.
+var containerClient container.Client
+
+// getContainerRuntime returns the container runtime. It is detected on first use, because detecting it may run the
+// command line tools of several runtimes, which commands not using containers should not wait for.
+func getContainerRuntime() *container.Runtime {
+	if containerRuntime == nil {
+		runtime, err := container.DetectRuntime(config.Current.Runtime.Name, config.Current.Runtime.SocketPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		containerRuntime = runtime
+	}
+	return containerRuntime
+}
+
+// getContainerClient returns the client for the containers of the container runtime
+func getContainerClient() container.Client {
+	if containerClient == nil {
+		containerClient = getContainerRuntime().Client()
+	}
+	return containerClient
+}
+
 func copyFileFromAssets(src, dst string) (int64, error) {
 	source, err := asset.Assets.Open(src)
 	if err != nil {
@@ -92,20 +128,43 @@ func getRelativePersistentResourcePathByHash(hash string) string {
 	}
 }
 
-func retrieveCloudStorageCredentials(instanceIdentifier string, projectNamespace string, clusterIdentifier string) (err error, bucketName string, privateKey []byte) {
-	log.Info("Retrieving cloud storage access data from instance")
+func getInstanceInternalHost(instanceIdentifier string, projectNamespace string) string {
+	return "beach@" + instanceIdentifier + "." + projectNamespace
+}
 
-	internalHost := "beach@" + instanceIdentifier + "." + projectNamespace
+// getInstanceSSHArgs returns the ssh arguments for connecting to a Beach instance through the jump host of its cluster
+func getInstanceSSHArgs(instanceIdentifier string, projectNamespace string, clusterIdentifier string) []string {
 	jumpHost := ""
 	if clusterIdentifier != "" {
 		jumpHost = "beach@ssh." + clusterIdentifier + ".flownative.cloud"
 	} else {
 		jumpHost = "beach@ssh.flownative.cloud"
 	}
-	output, err := exec.RunCommand("ssh", []string{
-		"-J", jumpHost, internalHost,
+	return []string{"-J", jumpHost, getInstanceInternalHost(instanceIdentifier, projectNamespace)}
+}
+
+// shellQuote quotes the given string for use as a single argument in a remote shell command
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// runInstanceScript runs the given bash script in a Beach instance, with stdin and stdout connected to the given reader and writer
+func runInstanceScript(instanceIdentifier string, projectNamespace string, clusterIdentifier string, script string, stdin io.Reader, stdout io.Writer) error {
+	commandArgs := append(
+		getInstanceSSHArgs(instanceIdentifier, projectNamespace, clusterIdentifier),
+		"/bin/bash", "-c", shellQuote("set -o pipefail; "+script),
+	)
+	return exec.RunPipedCommand("ssh", commandArgs, stdin, stdout)
+}
+
+func retrieveCloudStorageCredentials(instanceIdentifier string, projectNamespace string, clusterIdentifier string) (err error, bucketName string, privateKey []byte) {
+	log.Info("Retrieving cloud storage access data from instance")
+
+	internalHost := getInstanceInternalHost(instanceIdentifier, projectNamespace)
+	output, err := exec.RunCommand("ssh", append(
+		getInstanceSSHArgs(instanceIdentifier, projectNamespace, clusterIdentifier),
 		"/bin/bash", "-c", "env | grep BEACH_GOOGLE_CLOUD_STORAGE_",
-	})
+	))
 	if err != nil {
 		return errors.New(fmt.Sprintf("failed connecting to instance with internal host %v - %v", internalHost, err)), "", []byte("")
 	}
@@ -144,7 +203,9 @@ func retrieveCloudStorageCredentials(instanceIdentifier string, projectNamespace
 	return nil, bucketName, privateKey
 }
 
-func startLocalBeach() error {
+// startLocalBeach starts the reverse proxy, the default database server and the given database server, unless
+// they are running already
+func startLocalBeach(server *databaseServer) error {
 	_, err := os.Stat(path.Base)
 	if os.IsNotExist(err) {
 		err = setupLocalBeach()
@@ -153,56 +214,145 @@ func startLocalBeach() error {
 		}
 	}
 
-	nginxStatusOutput, err := exec.RunCommand("docker", []string{"ps", "--filter", "name=local_beach_nginx", "--filter", "status=running", "-q"})
+	nginxIsRunning, err := isContainerRunning("local_beach_nginx")
 	if err != nil {
-		return errors.New("failed checking status of container local_beach_nginx container, maybe the Docker daemon is not running")
+		return err
 	}
 
-	databaseStatusOutput, err := exec.RunCommand("docker", []string{"ps", "--filter", "name=local_beach_database", "--filter", "status=running", "-q"})
+	databaseIsRunning, err := isContainerRunning(databaseContainerName)
 	if err != nil {
-		return errors.New("failed checking status of container local_beach_database container")
+		return err
 	}
 
-	if len(nginxStatusOutput) == 0 || len(databaseStatusOutput) == 0 {
-		composeFileContent := readFileFromAssets("local-beach/docker-compose.yml")
-		composeFileContent = strings.ReplaceAll(composeFileContent, "{{databasePath}}", path.Database)
-		composeFileContent = strings.ReplaceAll(composeFileContent, "{{certificatesPath}}", path.Certificates)
+	serverIsRunning, err := isContainerRunning(server.ContainerName)
+	if err != nil {
+		return err
+	}
 
-		destination, err := os.Create(filepath.Join(path.Base, "docker-compose.yml"))
+	dnsIsRunning := true
+	if config.Current.DNS.Enabled {
+		dnsIsRunning, err = isContainerRunning(dnsContainerName)
 		if err != nil {
-			log.Error("failed creating docker-compose.yml: ", err)
-		} else {
-			_, err = destination.WriteString(composeFileContent)
-			if err != nil {
-				log.Error(err)
-			}
+			return err
+		}
+	}
+
+	if !nginxIsRunning || !databaseIsRunning || !serverIsRunning || !dnsIsRunning {
+		err = writeLocalBeachComposeFile(server)
+		if err != nil {
+			log.Error(err)
+		}
 
+		services := []string{"webserver", "database"}
+		if config.Current.DNS.Enabled {
+			services = append(services, "dns")
+		}
+		if !server.IsDefault() {
+			services = append(services, server.ServiceName)
 		}
-		_ = destination.Close()
 
 		log.Info("Starting reverse proxy and database server ...")
-		commandArgs := []string{"compose", "-f", filepath.Join(path.Base, "docker-compose.yml"), "up", "--remove-orphans", "-d"}
-		err = exec.RunInteractiveCommand("docker", commandArgs)
+		startedAt := time.Now()
+		commandArgs := append([]string{"compose", "-f", filepath.Join(path.Base, "docker-compose.yml"), "up", "--remove-orphans", "-d"}, services...)
+		err = exec.RunInteractiveCommand(getContainerRuntime().Command, commandArgs)
 		if err != nil {
 			return errors.New("container startup failed")
 		}
 
 		log.Info("Waiting for database server ...")
-		tries := 1
-		for {
-			output, err := exec.RunCommand("docker", []string{"inspect", "-f", "{{.State.Health.Status}}", "local_beach_database"})
+		err = waitForDatabaseServer(getDatabaseServer(""), startedAt)
+		if err != nil {
+			return err
+		}
+		if !server.IsDefault() {
+			log.Info("Waiting for database server " + server.ContainerName + " ...")
+			err = waitForDatabaseServer(server, startedAt)
 			if err != nil {
-				return errors.New("failed to check for database server container health")
-			}
-			if strings.TrimSpace(output) == "healthy" {
-				break
-			}
-			if tries == 10 {
-				return errors.New("timeout waiting for database server to start")
+				return err
 			}
-			tries++
-			time.Sleep(3 * time.Second)
 		}
 	}
 	return nil
 }
+
+// isContainerRunning checks if a container with the given name is running
+func isContainerRunning(containerName string) (bool, error) {
+	containerDetails, err := getContainerClient().Inspect(context.Background(), containerName)
+	if errors.Is(err, container.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.New("failed checking status of container " + containerName + ": " + err.Error())
+	}
+	return containerDetails.IsRunning(), nil
+}
+
+// printLastContainerLogs prints the last log lines of the given container to stderr, for diagnosing problems
+func printLastContainerLogs(containerName string) {
+	_, _ = fmt.Fprintln(os.Stderr, "Last log lines of "+containerName+":")
+	err := getContainerClient().Logs(context.Background(), containerName, container.LogsOptions{Tail: 20}, os.Stderr, os.Stderr)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "  (failed retrieving logs: "+err.Error()+")")
+	}
+}
+
+func formatByteSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	divisor, exponent := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		divisor *= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(divisor), "KMGTPE"[exponent])
+}
+
+// askForInput prints the given prompt and returns the line entered by the user
+func askForInput(prompt string) string {
+	fmt.Print(prompt)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func getProjectRegistryPathAndFilename() string {
+	return filepath.Join(path.Base, "projects.json")
+}
+
+// registerProject adds the project to the project registry or updates it, failures are only logged
+func registerProject(name string, rootPath string, started bool) {
+	projectRegistry, err := registry.Load(getProjectRegistryPathAndFilename())
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	project := projectRegistry.Register(name, rootPath)
+	if started {
+		now := time.Now()
+		project.LastStartedAt = &now
+	}
+	if err := projectRegistry.Save(); err != nil {
+		log.Warn("Failed saving project registry: ", err)
+	}
+}
+
+// getSandboxURL returns the URL of the first virtual host of the given sandbox
+func getSandboxURL(sandbox *beachsandbox.BeachSandbox) string {
+	if len(sandbox.Config.VirtualHosts) > 0 {
+		return "http://" + sandbox.Config.VirtualHosts[0]
+	}
+	return "http://" + strings.ToLower(sandbox.ProjectName) + "." + config.Current.Domain
+}
+
+// getSandboxEnvironment returns the environment for running Docker Compose for the given sandbox, with
+// defaults derived from the global configuration, which can be overridden by the sandbox configuration
+func getSandboxEnvironment(sandbox *beachsandbox.BeachSandbox) []string {
+	server := getSandboxDatabaseServer(sandbox)
+	environment := []string{
+		"BEACH_DATABASE_HOST=" + server.Host(),
+		"BEACH_DATABASE_PORT=" + strconv.Itoa(server.Port),
+		"BEACH_DATABASE_PASSWORD=" + config.Current.Database.RootPassword,
+	}
+	return append(environment, sandbox.Config.Environ()...)
+}
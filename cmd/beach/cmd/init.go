@@ -20,12 +20,16 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/config"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 var projectName string
 var flowRootPath string
+var domain string
+var databaseDriver string
 
 // initCmd represents the init command
 var initCmd = &cobra.Command{
@@ -39,6 +43,8 @@ var initCmd = &cobra.Command{
 func init() {
 	initCmd.Flags().StringVar(&projectName, "project-name", "", "Defines the project name, defaults to folder name.")
 	initCmd.Flags().StringVar(&flowRootPath, "flow-path", "", "Defines the Flow project root, defaults to current folder.")
+	initCmd.Flags().StringVar(&domain, "domain", "", "Defines the domain for the virtual host, defaults to the globally configured domain.")
+	initCmd.Flags().StringVar(&databaseDriver, "database-driver", beachsandbox.DatabaseDriverMySQL, "Defines the database driver, pdo_mysql for MariaDB or pdo_pgsql for PostgreSQL.")
 	rootCmd.AddCommand(initCmd)
 }
 
@@ -65,6 +71,21 @@ func handleInitRun(cmd *cobra.Command, args []string) {
 
 	log.Info("Project name set as " + projectName)
 
+	err = validateDatabaseDriver(databaseDriver)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	domain = strings.Trim(domain, " .")
+	if len(domain) == 0 {
+		domain = config.Current.Domain
+	}
+	if !config.IsValidDomain(domain) {
+		log.Fatal("The domain " + domain + " is not a valid domain name, it may only contain lowercase letters, digits, dots and dashes.")
+		return
+	}
+
 	_, err = copyFileFromAssets("project/.localbeach.docker-compose.yaml", ".localbeach.docker-compose.yaml")
 	if err != nil {
 		log.Fatal(err)
@@ -72,7 +93,17 @@ func handleInitRun(cmd *cobra.Command, args []string) {
 	}
 	log.Info("Created '.localbeach.docker-compose.yaml'.")
 
-	_, err = copyFileFromAssets("project/Settings.yaml", "Configuration/Development/Beach/Settings.yaml")
+	databaseCharset := "utf8mb4"
+	if databaseDriver == beachsandbox.DatabaseDriverPostgres {
+		databaseCharset = "UTF8"
+	}
+	settingsContent := strings.NewReplacer(
+		"{{databaseDriver}}", databaseDriver,
+		"{{databaseCharset}}", databaseCharset,
+	).Replace(readFileFromAssets("project/Settings.yaml"))
+
+	ensureDirectoryForFileExists("Configuration/Development/Beach/Settings.yaml")
+	err = os.WriteFile("Configuration/Development/Beach/Settings.yaml", []byte(settingsContent), 0644)
 	if err != nil {
 		log.Fatal(err)
 		return
@@ -86,6 +117,8 @@ func handleInitRun(cmd *cobra.Command, args []string) {
 	environmentContent = strings.ReplaceAll(environmentContent, "${BEACH_PROJECT_NAME_LOWERCASE}", strings.ToLower(projectName))
 	environmentContent = strings.ReplaceAll(environmentContent, "${BEACH_FLOW_ROOTPATH}", flowRootPath)
 	environmentContent = strings.ReplaceAll(environmentContent, "${BEACH_APPLICATION_PATH}", "/application/"+flowRootPath)
+	environmentContent = strings.ReplaceAll(environmentContent, "${LOCAL_BEACH_DOMAIN}", domain)
+	environmentContent = strings.ReplaceAll(environmentContent, "${BEACH_DATABASE_DRIVER}", databaseDriver)
 
 	destination, err := os.Create(".localbeach.dist.env")
 	if err != nil {
@@ -97,6 +130,11 @@ func handleInitRun(cmd *cobra.Command, args []string) {
 	destination.WriteString(environmentContent)
 	log.Info("Created '.localbeach.dist.env'.")
 
+	workingDirPath, err := os.Getwd()
+	if err == nil {
+		registerProject(projectName, workingDirPath, false)
+	}
+
 	log.Info("You are all set")
 	return
 }
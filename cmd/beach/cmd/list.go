@@ -0,0 +1,195 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/container"
+	"github.com/flownative/localbeach/pkg/registry"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var listJson bool
+var listOutputFormat string
+var listPrune bool
+
+// projectListEntry is a single project displayed by the list command
+type projectListEntry struct {
+	Name         string `json:"name" yaml:"name"`
+	RootPath     string `json:"rootPath" yaml:"rootPath"`
+	URL          string `json:"url" yaml:"url"`
+	PhpVersion   string `json:"phpVersion" yaml:"phpVersion"`
+	Status       string `json:"status" yaml:"status"`
+	DatabaseSize int64  `json:"databaseSize" yaml:"databaseSize"`
+}
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all Local Beach projects known on this computer",
+	Long: `list
+
+This command lists all Local Beach projects which were initialized or started
+on this computer, including their status and database size. Projects which
+are running but were not known yet are added automatically.
+
+The status is one of "running", "stopped" or "missing", the latter meaning
+that the project directory does not exist anymore. Use --prune to remove
+missing projects from the list.
+`,
+	Args: cobra.ExactArgs(0),
+	Run:  handleListRun,
+}
+
+func init() {
+	listCmd.Flags().BoolVar(&listJson, "json", false, "Output the list as JSON, same as --output json")
+	addOutputFlag(listCmd, &listOutputFormat)
+	listCmd.Flags().BoolVar(&listPrune, "prune", false, "Remove projects whose directory does not exist anymore")
+	rootCmd.AddCommand(listCmd)
+}
+
+func handleListRun(cmd *cobra.Command, args []string) {
+	if listJson {
+		listOutputFormat = outputFormatJSON
+	}
+	if err := validateOutputFormat(listOutputFormat); err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	projectRegistry, err := registry.Load(getProjectRegistryPathAndFilename())
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	runningContainers := map[string]bool{}
+	containers, err := getContainerClient().List(context.Background(), container.ListOptions{})
+	if err != nil {
+		log.Debug("Failed retrieving running containers: ", err)
+	} else {
+		for _, containerDetails := range containers {
+			runningContainers[containerDetails.Name] = true
+		}
+
+		instanceRoots, err := findInstanceRoots()
+		if err == nil {
+			for _, instanceRoot := range instanceRoots {
+				sandbox, err := beachsandbox.GetSandbox(instanceRoot)
+				if err == nil || errors.Is(err, beachsandbox.ErrNoFlowFound) {
+					projectRegistry.Register(sandbox.ProjectName, instanceRoot)
+				}
+			}
+		}
+	}
+
+	databaseSizes := map[string]map[string]int64{}
+
+	var entries []projectListEntry
+	for _, project := range projectRegistry.Projects {
+		entry := projectListEntry{
+			Name:     project.Name,
+			RootPath: project.RootPath,
+			Status:   "stopped",
+		}
+
+		if !containsLocalBeachInstance(project.RootPath) {
+			if listPrune {
+				projectRegistry.Unregister(project.RootPath)
+				continue
+			}
+			entry.Status = "missing"
+		} else if sandbox, err := beachsandbox.GetSandbox(project.RootPath); err == nil || errors.Is(err, beachsandbox.ErrNoFlowFound) {
+			entry.URL = getSandboxURL(sandbox)
+			entry.PhpVersion = sandbox.Config.PhpImageVersion
+			server := getSandboxDatabaseServer(sandbox)
+			if runningContainers[server.ContainerName] {
+				if _, exists := databaseSizes[server.ContainerName]; !exists {
+					databaseSizes[server.ContainerName] = getDatabaseSizes(server)
+				}
+				entry.DatabaseSize = databaseSizes[server.ContainerName][project.Name]
+			}
+		}
+		if runningContainers[project.Name+"_php"] {
+			entry.Status = "running"
+		}
+		entries = append(entries, entry)
+	}
+
+	if projectRegistry.Changed() {
+		if err := projectRegistry.Save(); err != nil {
+			log.Warn("Failed saving project registry: ", err)
+		}
+	}
+
+	if entries == nil {
+		entries = []projectListEntry{}
+	}
+	if handled, err := printStructuredOutput(listOutputFormat, entries); handled || err != nil {
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if len(entries) == 0 {
+		log.Info("There are no known Local Beach projects, run \"beach init\" to create one")
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "NAME\tSTATUS\tURL\tPHP\tDATABASE\tPATH")
+	for _, entry := range entries {
+		databaseSize := ""
+		if entry.DatabaseSize > 0 {
+			databaseSize = formatByteSize(entry.DatabaseSize)
+		}
+		_, _ = fmt.Fprintf(writer, "%v\t%v\t%v\t%v\t%v\t%v\n", entry.Name, entry.Status, entry.URL, entry.PhpVersion, databaseSize, entry.RootPath)
+	}
+	_ = writer.Flush()
+	return
+}
+
+// getDatabaseSizes returns the size of each database on the given server in
+// bytes, indexed by database name
+func getDatabaseSizes(server *databaseServer) map[string]int64 {
+	statement := "SELECT table_schema, COALESCE(SUM(data_length + index_length), 0) FROM information_schema.tables GROUP BY table_schema"
+	if server.Driver == beachsandbox.DatabaseDriverPostgres {
+		statement = "SELECT datname, pg_database_size(datname) FROM pg_database"
+	}
+
+	databaseSizes := map[string]int64{}
+	output, err := server.executeStatement(statement)
+	if err != nil {
+		log.Debug("Failed retrieving database sizes: ", err)
+		return databaseSizes
+	}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		columns := strings.Split(line, "\t")
+		if len(columns) == 2 {
+			databaseSizes[columns[0]], _ = strconv.ParseInt(columns[1], 10, 64)
+		}
+	}
+	return databaseSizes
+}
@@ -46,7 +46,7 @@ Docker containers (--containers).`,
 			if follow {
 				commandArgs = append(commandArgs, "-f")
 			}
-			err = exec.RunInteractiveCommand("docker", commandArgs)
+			err = exec.RunInteractiveCommandWithEnv(getContainerRuntime().Command, commandArgs, getSandboxEnvironment(sandbox))
 			if err != nil {
 				log.Fatal(err)
 				return
@@ -59,7 +59,7 @@ Docker containers (--containers).`,
 				commandArgs = append(commandArgs, "tail -n -"+strconv.Itoa(tail)+" "+"/application/"+sandbox.FlowRootPath+"/Data/Logs/*.log")
 			}
 
-			err = exec.RunInteractiveCommand("docker", commandArgs)
+			err = exec.RunInteractiveCommand(getContainerRuntime().Command, commandArgs)
 			if err != nil {
 				log.Fatal(err)
 				return
@@ -0,0 +1,63 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputFormatTable = "table"
+	outputFormatJSON  = "json"
+	outputFormatYAML  = "yaml"
+)
+
+// addOutputFlag adds the --output flag to the given command
+func addOutputFlag(cmd *cobra.Command, outputFormat *string) {
+	cmd.Flags().StringVarP(outputFormat, "output", "o", outputFormatTable, "Output format, one of: table, json, yaml")
+}
+
+// validateOutputFormat returns an error if the given output format is not supported
+func validateOutputFormat(outputFormat string) error {
+	switch outputFormat {
+	case outputFormatTable, outputFormatJSON, outputFormatYAML:
+		return nil
+	}
+	return errors.New("unsupported output format \"" + outputFormat + "\", must be one of: table, json, yaml")
+}
+
+// printStructuredOutput prints the given value as JSON or YAML, depending on the output format. It returns
+// false if the format is "table", leaving the rendering to the caller.
+func printStructuredOutput(outputFormat string, value interface{}) (bool, error) {
+	switch outputFormat {
+	case outputFormatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return true, encoder.Encode(value)
+	case outputFormatYAML:
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return true, encoder.Encode(value)
+	case outputFormatTable:
+		return false, nil
+	}
+	return false, validateOutputFormat(outputFormat)
+}
@@ -47,7 +47,14 @@ func handleRestartRun(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	err = startLocalBeach()
+	server := getSandboxDatabaseServer(sandbox)
+	err = startLocalBeach(server)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	err = prepareSandboxDatabase(sandbox, server)
 	if err != nil {
 		log.Fatal(err)
 		return
@@ -62,7 +69,7 @@ func handleRestartRun(cmd *cobra.Command, args []string) {
 		commandArgs = append(commandArgs, "stop")
 	}
 
-	err = exec.RunInteractiveCommand("docker", commandArgs)
+	err = exec.RunInteractiveCommandWithEnv(getContainerRuntime().Command, commandArgs, getSandboxEnvironment(sandbox))
 	if err != nil {
 		log.Fatal(err)
 		return
@@ -71,7 +78,7 @@ func handleRestartRun(cmd *cobra.Command, args []string) {
 	if restartPull {
 		log.Debug("Pulling images ...")
 		commandArgs = []string{"compose", "-f", sandbox.DockerComposeFilePath, "pull"}
-		output, err := exec.RunCommand("docker", commandArgs)
+		output, err := exec.RunCommandWithEnv(getContainerRuntime().Command, commandArgs, getSandboxEnvironment(sandbox))
 		if err != nil {
 			log.Fatal(output)
 			return
@@ -81,13 +88,13 @@ func handleRestartRun(cmd *cobra.Command, args []string) {
 	log.Debug("Starting containers ...")
 
 	commandArgs = []string{"compose", "-f", sandbox.DockerComposeFilePath, "up", "--remove-orphans", "-d"}
-	output, err := exec.RunCommand("docker", commandArgs)
+	output, err := exec.RunCommandWithEnv(getContainerRuntime().Command, commandArgs, getSandboxEnvironment(sandbox))
 	if err != nil {
 		log.Fatal(output)
 		return
 	}
 
 	log.Info("Local Beach instance was restarted.")
-	log.Info("When files have been synced, you can access this instance at http://" + sandbox.ProjectName + ".localbeach.net")
+	log.Info("When files have been synced, you can access this instance at " + getSandboxURL(sandbox))
 	return
 }
@@ -37,8 +37,9 @@ func init() {
 
 func handleResumeRun(cmd *cobra.Command, args []string) {
 	log.Info("Starting reverse proxy and database server ...")
-	commandArgs := []string{"compose", "-f", filepath.Join(path.Base, "docker-compose.yml"), "start", "webserver", "database"}
-	output, err := exec.RunCommand("docker", commandArgs)
+	commandArgs := []string{"compose", "-f", filepath.Join(path.Base, "docker-compose.yml"), "start", "webserver"}
+	commandArgs = append(commandArgs, getDatabaseServiceNames()...)
+	output, err := exec.RunCommand(getContainerRuntime().Command, commandArgs)
 	if err != nil {
 		log.Fatal(output)
 		return
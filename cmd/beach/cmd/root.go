@@ -15,17 +15,29 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/config"
+	"github.com/flownative/localbeach/pkg/path"
+	"github.com/flownative/localbeach/pkg/registry"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+var selectedProjectName, selectedRootPath string
+
+// configurationError is the error which occurred while loading the global configuration, if any
+var configurationError error
+
 var rootCmd = &cobra.Command{
-	Use:   "beach",
-	Short: "Beach and Local Beach support for the command line",
-	Long:  `beach is the tool for managing projects in Beach and Local Beach.`,
+	Use:              "beach",
+	Short:            "Beach and Local Beach support for the command line",
+	Long:             `beach is the tool for managing projects in Beach and Local Beach.`,
+	PersistentPreRun: handleRootPersistentPreRun,
 }
 
 // Execute runs this tool
@@ -43,7 +55,72 @@ func init() {
 		DisableLevelTruncation: true,
 	})
 	log.SetLevel(log.DebugLevel)
+
+	rootCmd.PersistentFlags().StringVar(&selectedProjectName, "project", "", "Name of a registered project to use instead of the one in the current directory")
+	rootCmd.PersistentFlags().StringVar(&selectedRootPath, "root", "", "Path of a project to use instead of the one in the current directory")
 }
 
 func initConfig() {
+	// Commands which need the configuration refuse to run if it is invalid, the doctor command reports the error
+	configurationError = config.Load(filepath.Join(path.Base, "config.yaml"))
+
+	if config.Current.Paths.Certificates != "" {
+		path.Certificates = config.Current.Paths.Certificates
+	}
+	if config.Current.Paths.Database != "" {
+		path.Database = config.Current.Paths.Database
+	}
+	if config.Current.Paths.Postgres != "" {
+		path.Postgres = config.Current.Paths.Postgres
+	}
+
+	err := selectSandbox()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+}
+
+func handleRootPersistentPreRun(cmd *cobra.Command, args []string) {
+	if configurationError != nil && !isIndependentOfConfiguration(cmd) {
+		log.Fatal(configurationError)
+		return
+	}
+}
+
+// isIndependentOfConfiguration returns true for the commands which work without a valid global configuration
+func isIndependentOfConfiguration(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case "doctor", "version", "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return cmd.Parent() == cmd.Root()
+	}
+	return cmd.Parent() != nil && cmd.Parent().Name() == "completion" && cmd.Parent().Parent() == cmd.Root()
+}
+
+// selectSandbox makes the project given by --project or --root the active sandbox
+func selectSandbox() error {
+	if selectedProjectName != "" && selectedRootPath != "" {
+		return errors.New("--project and --root cannot be used together")
+	}
+
+	if selectedRootPath != "" {
+		return beachsandbox.SetActiveRootPath(selectedRootPath)
+	}
+
+	if selectedProjectName != "" {
+		projectRegistry, err := registry.Load(getProjectRegistryPathAndFilename())
+		if err != nil {
+			return err
+		}
+		projects := projectRegistry.FindByName(selectedProjectName)
+		switch len(projects) {
+		case 0:
+			return fmt.Errorf("unknown project %v, run \"beach list\" to see all known projects", selectedProjectName)
+		case 1:
+			return beachsandbox.SetActiveRootPath(projects[0].RootPath)
+		default:
+			return fmt.Errorf("there are %d projects named %v, use --root to select one of them", len(projects), selectedProjectName)
+		}
+	}
+	return nil
 }
@@ -15,9 +15,14 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"path/filepath"
 
+	"github.com/flownative/localbeach/pkg/certificates"
+	"github.com/flownative/localbeach/pkg/config"
+	"github.com/flownative/localbeach/pkg/container"
 	"github.com/flownative/localbeach/pkg/exec"
 	"github.com/flownative/localbeach/pkg/path"
 	log "github.com/sirupsen/logrus"
@@ -37,45 +42,95 @@ var setupHttpsCmd = &cobra.Command{
 }
 
 func init() {
-	setupHttpsCmd.Flags().StringVar(&host, "host", "*.localbeach.net", "Host to use for the certificate. Multiple can be given comma-separated.")
+	setupHttpsCmd.Flags().StringVar(&host, "host", "", "Host to use for the certificate, defaults to a wildcard for the configured domain. Multiple can be given comma-separated.")
 	rootCmd.AddCommand(setupHttpsCmd)
 }
 
 func handleSetupHttpsRun(cmd *cobra.Command, args []string) {
 	log.Info("Setting up HTTPS for Local Beach.")
-	log.Info("You will be asked for your password in order to install the CA certificate")
 
-	commandArgs := []string{"-install"}
-	err := exec.RunInteractiveCommand("mkcert", commandArgs)
+	if len(host) == 0 {
+		host = "*." + config.Current.Domain
+	}
+
+	err := migrateCertificateAuthority()
+	if err != nil {
+		log.Fatal("Failed moving the certificate authority: ", err)
+		return
+	}
+	authority, created, err := certificates.LoadOrCreateAuthority(path.CertificateAuthority)
+	if err != nil {
+		log.Fatal("Failed setting up the certificate authority: ", err)
+		return
+	}
+	if created {
+		log.Info("Created certificate authority " + authority.Certificate.Subject.CommonName)
+	}
+
+	log.Info("You will be asked for your password in order to install the CA certificate")
+	err = authority.Install()
 	if err != nil {
 		log.Error(err)
 		return
 	}
 
-	commandArgs = []string{"-cert-file", filepath.Join(path.Certificates, "default.crt"), "-key-file", filepath.Join(path.Certificates, "default.key")}
+	var hostnames []string
 	for _, hostname := range strings.Split(host, ",") {
-		commandArgs = append(commandArgs, strings.Trim(hostname, " "))
+		hostnames = append(hostnames, strings.Trim(hostname, " "))
 	}
-	err = exec.RunInteractiveCommand("mkcert", commandArgs)
+	err = authority.Issue(path.Certificates, "default", hostnames)
 	if err != nil {
 		log.Error(err)
 		return
 	}
+	log.Info("Created default certificate for " + strings.Join(hostnames, ", "))
+	log.Info("Firefox does not use the system trust store, see \"beach cert export-ca --help\" for making it trust Local Beach certificates")
+
+	err = reloadReverseProxy()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
 
-	nginxStatusOutput, err := exec.RunCommand("docker", []string{"ps", "--filter", "name=local_beach_nginx", "--filter", "status=running", "-q"})
+	return
+}
+
+// reloadReverseProxy regenerates the configuration of the reverse proxy, if it is running, so that it picks
+// up changed certificates without interrupting connections. If that fails, the reverse proxy is restarted.
+func reloadReverseProxy() error {
+	running, err := isContainerRunning("local_beach_nginx")
+	if err != nil || !running {
+		return err
+	}
+
+	log.Debug("Reloading reverse proxy ...")
+	var output bytes.Buffer
+	exitCode, err := getContainerClient().Exec(context.Background(), "local_beach_nginx", container.ExecOptions{
+		Command: []string{"sh", "-c", "docker-gen /app/nginx.tmpl /etc/nginx/conf.d/default.conf && nginx -s reload"},
+		Stdout:  &output,
+		Stderr:  &output,
+	})
+	if err != nil || exitCode != 0 {
+		log.Debug("Failed reloading reverse proxy: " + strings.TrimSpace(output.String()))
+		return restartReverseProxy()
+	}
+	return nil
+}
+
+// restartReverseProxy restarts the reverse proxy, if it is running, so that it picks up changed certificates
+func restartReverseProxy() error {
+	running, err := isContainerRunning("local_beach_nginx")
 	if err != nil {
-		log.Error(errors.New("failed checking status of container local_beach_nginx container"))
+		return err
 	}
 
-	if len(nginxStatusOutput) != 0 {
+	if running {
 		log.Info("Restarting reverse proxy ...")
-		commandArgs = []string{"compose", "-f", filepath.Join(path.Base, "docker-compose.yml"), "restart"}
-		output, err := exec.RunCommand("docker", commandArgs)
+		commandArgs := []string{"compose", "-f", filepath.Join(path.Base, "docker-compose.yml"), "restart", "webserver"}
+		output, err := exec.RunCommand(getContainerRuntime().Command, commandArgs)
 		if err != nil {
-			log.Fatal(output)
-			return
+			return errors.New(output)
 		}
 	}
-
-	return
+	return nil
 }
@@ -15,10 +15,14 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/config"
 	"github.com/flownative/localbeach/pkg/exec"
 	"github.com/flownative/localbeach/pkg/path"
 	log "github.com/sirupsen/logrus"
@@ -49,7 +53,7 @@ func migrateOldBase() error {
 
 		log.Info("stopping reverse proxy and database server")
 		commandArgs := []string{"compose", "-f", filepath.Join(path.OldBase, "docker-compose.yml"), "rm", "--force", "--stop", "-v"}
-		output, err := exec.RunCommand("docker", commandArgs)
+		output, err := exec.RunCommand(getContainerRuntime().Command, commandArgs)
 		if err != nil {
 			log.Error(output)
 		}
@@ -120,21 +124,86 @@ func setupLocalBeach() error {
 		log.Error(err)
 	}
 
+	err = writeLocalBeachComposeFile()
+	if err != nil {
+		log.Error(err)
+	}
+
+	return nil
+}
+
+// writeLocalBeachComposeFile renders the Docker Compose configuration for the reverse proxy and database
+// servers, using the global configuration. Besides the default database server, it contains the additional
+// servers used by registered projects and the given servers.
+func writeLocalBeachComposeFile(requiredServers ...*databaseServer) error {
+	if len(getContainerRuntime().MountSocketPath) == 0 {
+		return errors.New("the reverse proxy needs a Docker compatible API socket, which " + getContainerRuntime().Name + " does not provide, please configure runtime.socketPath")
+	}
+
 	composeFileContent := readFileFromAssets("local-beach/docker-compose.yml")
-	composeFileContent = strings.ReplaceAll(composeFileContent, "{{databasePath}}", path.Database)
-	composeFileContent = strings.ReplaceAll(composeFileContent, "{{certificatesPath}}", path.Certificates)
+	composeFileContent = strings.NewReplacer(
+		"{{containerSocketPath}}", getContainerRuntime().MountSocketPath,
+		"{{databasePath}}", path.Database,
+		"{{certificatesPath}}", path.Certificates,
+		"{{domain}}", config.Current.Domain,
+		"{{proxyImage}}", config.Current.Proxy.Image,
+		"{{databaseImage}}", config.Current.Database.Image,
+		"{{databaseRootPassword}}", config.Current.Database.RootPassword,
+		"{{httpPort}}", strconv.Itoa(config.Current.Ports.HTTP),
+		"{{httpsPort}}", strconv.Itoa(config.Current.Ports.HTTPS),
+		"{{databasePort}}", strconv.Itoa(config.Current.Ports.Database),
+	).Replace(composeFileContent)
+
+	if config.Current.DNS.Enabled {
+		dnsArguments := []string{"--no-resolv", "--no-hosts", "--log-facility=-", "--address=/" + config.Current.Domain + "/127.0.0.1"}
+		for _, upstream := range config.Current.DNS.Upstream {
+			dnsArguments = append(dnsArguments, "--server="+upstream)
+		}
+		for i, argument := range dnsArguments {
+			dnsArguments[i] = strconv.Quote(argument)
+		}
 
-	destination, err := os.Create(filepath.Join(path.Base, "docker-compose.yml"))
-	if err != nil {
-		log.Error("failed creating docker-compose.yml: ", err)
-	} else {
-		_, err = destination.WriteString(composeFileContent)
-		if err != nil {
-			log.Error(err)
+		composeFileContent = strings.TrimRight(composeFileContent, "\n") + "\n" + strings.NewReplacer(
+			"{{dnsImage}}", config.Current.DNS.Image,
+			"{{dnsPort}}", strconv.Itoa(config.Current.DNS.Port),
+			"{{dnsArguments}}", "["+strings.Join(dnsArguments, ", ")+"]",
+		).Replace(readFileFromAssets("local-beach/docker-compose.dns.yml"))
+	}
+
+	knownServers := map[string]bool{}
+	for _, server := range append(getAdditionalDatabaseServers(), requiredServers...) {
+		if server.IsDefault() || knownServers[server.ServiceName] {
+			continue
 		}
+		knownServers[server.ServiceName] = true
 
+		if err := migrateDatabaseServerDataPath(server); err != nil {
+			return errors.New("failed moving database directory: " + err.Error())
+		}
+		if err := os.MkdirAll(server.DataPath, os.ModePerm); err != nil {
+			return errors.New("failed creating database directory: " + err.Error())
+		}
+		if server.Driver == beachsandbox.DatabaseDriverPostgres {
+			composeFileContent = strings.TrimRight(composeFileContent, "\n") + "\n" + strings.NewReplacer(
+				"{{postgresImage}}", server.Image,
+				"{{postgresPath}}", server.DataPath,
+				"{{postgresPassword}}", config.Current.Postgres.Password,
+				"{{postgresPort}}", strconv.Itoa(config.Current.Ports.Postgres),
+			).Replace(readFileFromAssets("local-beach/docker-compose.postgres.yml"))
+			continue
+		}
+		composeFileContent = strings.TrimRight(composeFileContent, "\n") + "\n" + strings.NewReplacer(
+			"{{serviceName}}", server.ServiceName,
+			"{{containerName}}", server.ContainerName,
+			"{{databaseImage}}", server.Image,
+			"{{databasePath}}", server.DataPath,
+			"{{databaseRootPassword}}", config.Current.Database.RootPassword,
+		).Replace(readFileFromAssets("local-beach/docker-compose.database.yml"))
 	}
-	_ = destination.Close()
 
+	err := os.WriteFile(filepath.Join(path.Base, "docker-compose.yml"), []byte(composeFileContent), 0644)
+	if err != nil {
+		return errors.New("failed creating docker-compose.yml: " + err.Error())
+	}
 	return nil
 }
@@ -15,26 +15,60 @@
 package cmd
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/flownative/localbeach/pkg/beachsandbox"
+	"github.com/flownative/localbeach/pkg/config"
+	"github.com/flownative/localbeach/pkg/container"
 	"github.com/flownative/localbeach/pkg/exec"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+const defaultStartWaitTimeout = 3 * time.Minute
+
 var startPull bool
+var startWait time.Duration
 
 // startCmd represents the start command
 var startCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start the Local Beach instance in the current directory",
-	Long:  "",
-	Args:  cobra.ExactArgs(0),
-	Run:   handleStartRun,
+	Long: `Start the Local Beach instance in the current directory.
+
+By default, the command returns as soon as the containers were created. With
+--wait, it waits until all containers of the project and the reverse proxy are
+running and healthy and the project URL responds. The default timeout is 3
+minutes, use for example --wait=10m for a different one. The timeout must be
+given with an equals sign, because "--wait 10m" is read as --wait followed by
+an argument. If the project does not become ready in time, the last log lines
+of the affected containers are shown.`,
+	Args: validateStartArgs,
+	Run:  handleStartRun,
 }
 
 func init() {
 	rootCmd.AddCommand(startCmd)
 	startCmd.Flags().BoolVarP(&startPull, "pull", "p", false, "Pull images before start")
+	startCmd.Flags().DurationVar(&startWait, "wait", 0, "Wait until the project is ready, optionally with a timeout given with an equals sign, for example --wait=5m")
+	startCmd.Flags().Lookup("wait").NoOptDefVal = defaultStartWaitTimeout.String()
+}
+
+// validateStartArgs rejects all arguments, with a hint if the timeout of --wait was given without an equals sign
+func validateStartArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 && cmd.Flags().Changed("wait") {
+		if _, err := time.ParseDuration(args[0]); err == nil {
+			return errors.New("the timeout of --wait must be given with an equals sign, use --wait=" + args[0])
+		}
+	}
+	return cobra.ExactArgs(0)(cmd, args)
 }
 
 func handleStartRun(cmd *cobra.Command, args []string) {
@@ -46,7 +80,8 @@ func handleStartRun(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	err = startLocalBeach()
+	server := getSandboxDatabaseServer(sandbox)
+	err = startLocalBeach(server)
 	if err != nil {
 		log.Fatal(err)
 		return
@@ -55,29 +90,177 @@ func handleStartRun(cmd *cobra.Command, args []string) {
 	if startPull {
 		log.Debug("Pulling images ...")
 		commandArgs = []string{"compose", "-f", sandbox.DockerComposeFilePath, "pull"}
-		output, err := exec.RunCommand("docker", commandArgs)
+		output, err := exec.RunCommandWithEnv(getContainerRuntime().Command, commandArgs, getSandboxEnvironment(sandbox))
 		if err != nil {
 			log.Fatal(output)
 			return
 		}
 	}
 
+	err = prepareSandboxDatabase(sandbox, server)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	registerProject(sandbox.ProjectName, sandbox.ProjectRootPath, true)
+
+	issuedHosts, err := provisionCertificates(sandbox)
+	if err != nil {
+		log.Warn("Failed issuing certificates: ", err)
+	}
+	if len(issuedHosts) > 0 {
+		log.Info("Issued certificates for " + strings.Join(issuedHosts, ", "))
+	}
+
 	log.Info("Starting project ...")
 	commandArgs = []string{"compose", "-f", sandbox.DockerComposeFilePath, "up", "--remove-orphans", "-d"}
-	output, err := exec.RunCommand("docker", commandArgs)
+	output, err := exec.RunCommandWithEnv(getContainerRuntime().Command, commandArgs, getSandboxEnvironment(sandbox))
 	if err != nil {
 		log.Fatal(output)
 		return
 	}
 
-	log.Debug("Creating project database (if needed) ...")
-	commandArgs = []string{"exec", "local_beach_database", "/bin/bash", "-c", "echo 'CREATE DATABASE IF NOT EXISTS `" + sandbox.ProjectName + "`' | mysql -u root --password=password"}
-	output, err = exec.RunCommand("docker", commandArgs)
-	if err != nil {
-		log.Fatal(output)
+	if len(issuedHosts) > 0 {
+		err = reloadReverseProxy()
+		if err != nil {
+			log.Warn("Failed reloading reverse proxy: ", err)
+		}
+	}
+
+	if startWait > 0 {
+		err = waitForSandbox(sandbox, time.Now().Add(startWait))
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		log.Info("You are all set, you can access this instance at " + getSandboxURL(sandbox))
 		return
 	}
 
 	log.Info("You are all set")
-	log.Info("When files have been synced, you can access this instance at http://" + sandbox.ProjectName + ".localbeach.net")
+	log.Info("When files have been synced, you can access this instance at " + getSandboxURL(sandbox))
+}
+
+// waitForSandbox waits until the containers of the given sandbox and the reverse proxy are ready and the
+// project URL responds. If that does not happen before the deadline, the last logs of the containers are shown.
+func waitForSandbox(sandbox *beachsandbox.BeachSandbox, deadline time.Time) error {
+	containers, err := getContainerClient().List(context.Background(), container.ListOptions{
+		All:    true,
+		Labels: map[string]string{"com.docker.compose.project.config_files": sandbox.DockerComposeFilePath},
+	})
+	if err != nil {
+		return err
+	}
+
+	containerNames := []string{"local_beach_nginx"}
+	for _, containerDetails := range containers {
+		containerNames = append(containerNames, containerDetails.Name)
+	}
+
+	log.Info("Waiting for containers to become ready ...")
+	pendingContainerNames := containerNames
+	lastProgressReport := time.Now()
+	for len(pendingContainerNames) > 0 {
+		var stillPendingContainerNames []string
+		for _, containerName := range pendingContainerNames {
+			ready, err := isContainerReady(containerName)
+			if err != nil {
+				printLastContainerLogs(containerName)
+				return err
+			}
+			if ready {
+				log.Info("Container " + containerName + " is ready")
+			} else {
+				stillPendingContainerNames = append(stillPendingContainerNames, containerName)
+			}
+		}
+		pendingContainerNames = stillPendingContainerNames
+		if len(pendingContainerNames) == 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			for _, containerName := range pendingContainerNames {
+				printLastContainerLogs(containerName)
+			}
+			return errors.New("timeout waiting for " + strings.Join(pendingContainerNames, ", ") + " to become ready")
+		}
+		if time.Since(lastProgressReport) >= 10*time.Second {
+			log.Info("Still waiting for " + strings.Join(pendingContainerNames, ", ") + " ...")
+			lastProgressReport = time.Now()
+		}
+		time.Sleep(time.Second)
+	}
+
+	url := getSandboxURL(sandbox)
+	log.Info("Waiting for " + url + " to respond ...")
+	err = waitForURL(url, deadline)
+	if err != nil {
+		for _, containerName := range containerNames {
+			printLastContainerLogs(containerName)
+		}
+		return err
+	}
+	return nil
+}
+
+// isContainerReady returns true if the given container is running and healthy, and an error if it stopped
+func isContainerReady(containerName string) (bool, error) {
+	containerDetails, err := getContainerClient().Inspect(context.Background(), containerName)
+	if err != nil {
+		return false, err
+	}
+	switch containerDetails.State {
+	case "running":
+		return containerDetails.Health == container.HealthNone || containerDetails.Health == container.HealthHealthy, nil
+	case "exited", "dead":
+		return false, errors.New("container " + containerName + " stopped unexpectedly")
+	}
+	return false, nil
+}
+
+// waitForURL requests the given URL from the reverse proxy until it responds with something other than a
+// gateway error. The request is sent to 127.0.0.1, so that it does not depend on DNS resolution.
+func waitForURL(url string, deadline time.Time) error {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network string, address string) (net.Conn, error) {
+				_, port, err := net.SplitHostPort(address)
+				if err != nil {
+					return nil, err
+				}
+				switch port {
+				case "80":
+					port = strconv.Itoa(config.Current.Ports.HTTP)
+				case "443":
+					port = strconv.Itoa(config.Current.Ports.HTTPS)
+				}
+				return dialer.DialContext(ctx, network, net.JoinHostPort("127.0.0.1", port))
+			},
+			// The certificate authority might not be trusted yet, but only readiness is of interest here
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	lastError := errors.New("the deadline passed before the first request")
+	for time.Now().Before(deadline) {
+		client.Timeout = time.Until(deadline)
+		response, err := client.Get(url)
+		if err == nil {
+			_ = response.Body.Close()
+			switch response.StatusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				lastError = errors.New("responded with " + response.Status)
+			default:
+				log.Info(url + " responded with " + response.Status)
+				return nil
+			}
+		} else {
+			lastError = err
+		}
+		time.Sleep(time.Second)
+	}
+	return errors.New("timeout waiting for " + url + " to respond, last error: " + lastError.Error())
 }
@@ -15,34 +15,94 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
 	"github.com/flownative/localbeach/pkg/beachsandbox"
-	"github.com/flownative/localbeach/pkg/exec"
+	"github.com/flownative/localbeach/pkg/path"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+var statusOutputFormat string
+
 // statusCmd represents the status command
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Display the status of the Local Beach instance containers",
-	Long:  "",
-	Run: func(cmd *cobra.Command, args []string) {
-		sandbox, err := beachsandbox.GetActiveSandbox()
-		if err != nil {
-			log.Fatal("Could not activate sandbox: ", err)
-			return
-		}
+	Long: `Display the status of the containers of the current project as well as the
+shared Local Beach services, like the reverse proxy and the database server.
+
+Use --output json or --output yaml for machine-readable output.`,
+	Args: cobra.ExactArgs(0),
+	Run:  handleStatusRun,
+}
+
+func init() {
+	addOutputFlag(statusCmd, &statusOutputFormat)
+	rootCmd.AddCommand(statusCmd)
+}
+
+func handleStatusRun(cmd *cobra.Command, args []string) {
+	if err := validateOutputFormat(statusOutputFormat); err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	sandbox, err := beachsandbox.GetActiveSandbox()
+	if err != nil {
+		log.Fatal("Could not activate sandbox: ", err)
+		return
+	}
 
-		commandArgs := []string{"compose", "-f", sandbox.DockerComposeFilePath, "ps"}
-		err = exec.RunInteractiveCommand("docker", commandArgs)
+	status, err := sandbox.GetStatus(getContainerRuntime().Command, filepath.Join(path.Base, "docker-compose.yml"), getSandboxEnvironment(sandbox))
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	if handled, err := printStructuredOutput(statusOutputFormat, status); handled || err != nil {
 		if err != nil {
 			log.Fatal(err)
-			return
 		}
 		return
-	},
+	}
+
+	fmt.Printf("Project: %s (%s)\n", status.ProjectName, status.ProjectRootPath)
+	if len(status.URLs) > 0 {
+		fmt.Printf("URLs:    %s\n", strings.Join(status.URLs, ", "))
+	}
+	fmt.Println()
+	printContainerStatusTable(status.Containers)
+	if len(status.SharedServices) > 0 {
+		fmt.Println()
+		fmt.Println("Local Beach services:")
+		printContainerStatusTable(status.SharedServices)
+	}
 }
 
-func init() {
-	rootCmd.AddCommand(statusCmd)
+func printContainerStatusTable(containers []beachsandbox.ContainerStatus) {
+	if len(containers) == 0 {
+		fmt.Println("No containers found")
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "NAME\tSERVICE\tIMAGE\tSTATE\tHEALTH\tPORTS")
+	for _, container := range containers {
+		var ports []string
+		for _, port := range container.Ports {
+			if port.HostPort > 0 {
+				ports = append(ports, port.HostIP+":"+strconv.Itoa(port.HostPort)+"->"+strconv.Itoa(port.ContainerPort)+"/"+port.Protocol)
+			} else {
+				ports = append(ports, strconv.Itoa(port.ContainerPort)+"/"+port.Protocol)
+			}
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n", container.Name, container.Service, container.Image, container.State, container.Health, strings.Join(ports, ", "))
+	}
+	_ = writer.Flush()
 }
@@ -16,11 +16,25 @@ package cmd
 
 import (
 	"fmt"
+	"runtime"
+
 	"github.com/flownative/localbeach/pkg/version"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+var versionOutputFormat string
+
+// versionInformation is the version information displayed by the version command
+type versionInformation struct {
+	Version         string `json:"version" yaml:"version"`
+	GoVersion       string `json:"goVersion" yaml:"goVersion"`
+	OperatingSystem string `json:"os" yaml:"os"`
+	Architecture    string `json:"arch" yaml:"arch"`
+}
+
 func init() {
+	addOutputFlag(versionCmd, &versionOutputFormat)
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -29,6 +43,18 @@ var versionCmd = &cobra.Command{
 	Short: "Print the version number of Beach",
 	Long:  `If version numbers are important to you, this command will be your favorite: it displays a version!`,
 	Run: func(cmd *cobra.Command, args []string) {
+		information := versionInformation{
+			Version:         version.Version,
+			GoVersion:       runtime.Version(),
+			OperatingSystem: runtime.GOOS,
+			Architecture:    runtime.GOARCH,
+		}
+		if handled, err := printStructuredOutput(versionOutputFormat, information); handled || err != nil {
+			if err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
 		fmt.Printf("Local Beach %s © 2019-2025 Robert Lemke / Flownative GmbH \n", version.Version)
 	},
 }
@@ -5,6 +5,7 @@ require (
 	github.com/sirupsen/logrus v1.9.3
 	github.com/spf13/cobra v1.9.1
 	google.golang.org/api v0.228.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
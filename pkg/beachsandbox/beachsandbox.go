@@ -17,27 +17,29 @@ package beachsandbox
 import (
 	"os"
 	"path/filepath"
-	"strings"
 )
 
 type BeachSandbox struct {
-	ProjectName                        string ``
-	ProjectRootPath                    string ``
-	ProjectDataPersistentResourcesPath string ``
-	DockerComposeFilePath              string ``
-	FlowRootPath                       string ``
+	ProjectName                        string  ``
+	ProjectRootPath                    string  ``
+	ProjectDataPersistentResourcesPath string  ``
+	DockerComposeFilePath              string  ``
+	FlowRootPath                       string  ``
+	Config                             *Config ``
 }
 
 func (sandbox *BeachSandbox) Init(rootPath string) error {
 	sandbox.ProjectRootPath = rootPath
 
-	if err := loadLocalBeachEnvironment(rootPath); err != nil {
+	config, err := LoadConfig(rootPath)
+	if err != nil {
 		return err
 	}
 
+	sandbox.Config = config
 	sandbox.DockerComposeFilePath = filepath.Join(sandbox.ProjectRootPath, ".localbeach.docker-compose.yaml")
-	sandbox.ProjectName = os.Getenv("BEACH_PROJECT_NAME")
-	sandbox.FlowRootPath = strings.Trim(os.Getenv("BEACH_FLOW_ROOTPATH"), "/")
+	sandbox.ProjectName = config.ProjectName
+	sandbox.FlowRootPath = config.FlowRootPath
 	sandbox.ProjectDataPersistentResourcesPath = filepath.Join(sandbox.ProjectRootPath, sandbox.FlowRootPath, "Data/Persistent/Resources")
 
 	if info, err := os.Stat(filepath.Join(sandbox.ProjectRootPath, sandbox.FlowRootPath, "flow")); err == nil && !info.IsDir() {
@@ -47,8 +49,30 @@ func (sandbox *BeachSandbox) Init(rootPath string) error {
 	return ErrNoFlowFound
 }
 
-// GetActiveSandbox returns the active sandbox based on the current working dir
+var activeRootPath string
+
+// SetActiveRootPath makes the project containing the given path the active sandbox, instead of the one
+// containing the current working dir
+func SetActiveRootPath(path string) error {
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	rootPath, err := detectProjectRootPath(absolutePath)
+	if err != nil {
+		return err
+	}
+	activeRootPath = rootPath
+	return nil
+}
+
+// GetActiveSandbox returns the active sandbox based on the current working dir, or the root path set
+// with SetActiveRootPath
 func GetActiveSandbox() (*BeachSandbox, error) {
+	if activeRootPath != "" {
+		return GetSandbox(activeRootPath)
+	}
+
 	rootPath, err := detectProjectRootPathFromWorkingDir()
 	if err != nil {
 		return nil, err
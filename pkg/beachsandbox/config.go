@@ -0,0 +1,148 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beachsandbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConfigFilenames are the environment files of a sandbox, in the order they are loaded. Values of later
+// files take precedence over values of earlier files.
+var ConfigFilenames = []string{".localbeach.dist.env", ".localbeach.env", ".env"}
+
+// The database drivers a sandbox can use, named like the Doctrine drivers in the Flow settings
+const (
+	DatabaseDriverMySQL    = "pdo_mysql"
+	DatabaseDriverPostgres = "pdo_pgsql"
+)
+
+// ConfigValue is a single configuration value and the location it was defined at
+type ConfigValue struct {
+	Key      string
+	Value    string
+	Filename string
+	Line     int
+
+	// Overrides contains the definitions of the same key which were replaced by this one, in the order they were loaded
+	Overrides []ConfigValue
+
+	endLine int
+}
+
+// Config is the merged configuration of a sandbox, loaded from its environment files
+type Config struct {
+	ProjectName     string
+	VirtualHosts    []string
+	PhpImageVersion string
+	FlowRootPath    string
+	ApplicationPath string
+	DatabaseImage   string
+	DatabaseDriver  string
+
+	values map[string]ConfigValue
+}
+
+// LoadConfig loads and merges the environment files found in the given project root path
+func LoadConfig(projectRootPath string) (*Config, error) {
+	config := &Config{values: map[string]ConfigValue{}}
+
+	for _, filename := range ConfigFilenames {
+		pathAndFilename := filepath.Join(projectRootPath, filename)
+		source, err := os.ReadFile(pathAndFilename)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, errors.New("failed loading environment file " + pathAndFilename + ": " + err.Error())
+		}
+
+		values, err := parseEnvFile(pathAndFilename, string(source), config.lookup)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			if previousValue, ok := config.values[value.Key]; ok {
+				overriddenValue := previousValue
+				overriddenValue.Overrides = nil
+				value.Overrides = append(previousValue.Overrides, overriddenValue)
+			}
+			config.values[value.Key] = value
+		}
+	}
+
+	config.ProjectName = config.Get("BEACH_PROJECT_NAME")
+	config.PhpImageVersion = config.Get("BEACH_PHP_IMAGE_VERSION")
+	config.FlowRootPath = strings.Trim(config.Get("BEACH_FLOW_ROOTPATH"), "/")
+	config.ApplicationPath = config.Get("BEACH_APPLICATION_PATH")
+	config.DatabaseImage = config.Get("BEACH_DATABASE_IMAGE")
+	config.DatabaseDriver = config.Get("BEACH_DATABASE_DRIVER")
+	if len(config.DatabaseDriver) == 0 {
+		config.DatabaseDriver = DatabaseDriverMySQL
+	}
+	for _, virtualHost := range strings.Split(config.Get("BEACH_VIRTUAL_HOSTS"), ",") {
+		if virtualHost = strings.TrimSpace(virtualHost); len(virtualHost) > 0 {
+			config.VirtualHosts = append(config.VirtualHosts, virtualHost)
+		}
+	}
+
+	return config, nil
+}
+
+// Get returns the value of the given key, or an empty string if it is not defined
+func (config *Config) Get(key string) string {
+	value, _ := config.Lookup(key)
+	return value.Value
+}
+
+// Lookup returns the given key's value, including the location it was defined at
+func (config *Config) Lookup(key string) (ConfigValue, bool) {
+	value, ok := config.values[key]
+	return value, ok
+}
+
+// Values returns all values defined in the environment files, sorted by key
+func (config *Config) Values() []ConfigValue {
+	var values []ConfigValue
+	for _, value := range config.values {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Key < values[j].Key
+	})
+	return values
+}
+
+// Environ returns the environment of the current process, extended by the configuration values, for
+// passing it to commands like "docker compose"
+func (config *Config) Environ() []string {
+	environment := os.Environ()
+	if config == nil {
+		return environment
+	}
+	for _, value := range config.Values() {
+		environment = append(environment, value.Key+"="+value.Value)
+	}
+	return environment
+}
+
+func (config *Config) lookup(key string) (string, bool) {
+	if value, ok := config.values[key]; ok {
+		return value.Value, true
+	}
+	return os.LookupEnv(key)
+}
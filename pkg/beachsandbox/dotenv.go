@@ -0,0 +1,308 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beachsandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ParseError is returned if an environment file contains invalid syntax
+type ParseError struct {
+	Filename string
+	Line     int
+	Message  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v:%d: %v", e.Filename, e.Line, e.Message)
+}
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+var envPlainValuePattern = regexp.MustCompile(`^[A-Za-z0-9_./:,@%+=-]*$`)
+
+// parseEnvFile parses the given content using the .env file semantics of Docker Compose: values may be
+// quoted, span multiple lines and reference other variables, which are resolved through lookup.
+func parseEnvFile(filename string, content string, lookup func(string) (string, bool)) ([]ConfigValue, error) {
+	var values []ConfigValue
+	definedValues := map[string]string{}
+	lookupDefinedFirst := func(name string) (string, bool) {
+		if value, ok := definedValues[name]; ok {
+			return value, true
+		}
+		return lookup(name)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "export ") || strings.HasPrefix(line, "export\t") {
+			line = strings.TrimSpace(line[len("export"):])
+		}
+
+		name, rawValue, found := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !found {
+			return nil, &ParseError{filename, lineNumber, fmt.Sprintf("missing \"=\" after variable %v", name)}
+		}
+		if !envNamePattern.MatchString(name) {
+			return nil, &ParseError{filename, lineNumber, fmt.Sprintf("invalid variable name %q", name)}
+		}
+		rawValue = strings.TrimLeft(rawValue, " \t")
+
+		var value string
+		var err error
+		if len(rawValue) > 0 && (rawValue[0] == '"' || rawValue[0] == '\'') {
+			quote := rawValue[0]
+			body := rawValue[1:]
+			for {
+				end := findClosingQuote(body, quote)
+				if end >= 0 {
+					remainder := strings.TrimSpace(body[end+1:])
+					if len(remainder) > 0 && !strings.HasPrefix(remainder, "#") {
+						return nil, &ParseError{filename, lineNumber, fmt.Sprintf("unexpected characters after quoted value of %v", name)}
+					}
+					body = body[:end]
+					break
+				}
+				if i+1 >= len(lines) {
+					return nil, &ParseError{filename, lineNumber, fmt.Sprintf("unterminated quoted value of %v", name)}
+				}
+				i++
+				body += "\n" + lines[i]
+			}
+
+			if quote == '\'' {
+				value = body
+			} else {
+				value, err = expandEnvValue(body, lookupDefinedFirst, true)
+			}
+		} else {
+			if commentPosition := strings.Index(rawValue, " #"); commentPosition >= 0 {
+				rawValue = rawValue[:commentPosition]
+			}
+			if commentPosition := strings.Index(rawValue, "\t#"); commentPosition >= 0 {
+				rawValue = rawValue[:commentPosition]
+			}
+			value, err = expandEnvValue(strings.TrimSpace(rawValue), lookupDefinedFirst, false)
+		}
+		if err != nil {
+			return nil, &ParseError{filename, lineNumber, err.Error()}
+		}
+
+		definedValues[name] = value
+		values = append(values, ConfigValue{Key: name, Value: value, Filename: filename, Line: lineNumber, endLine: i + 1})
+	}
+
+	return values, nil
+}
+
+func findClosingQuote(body string, quote byte) int {
+	for i := 0; i < len(body); i++ {
+		if quote == '"' && body[i] == '\\' {
+			i++
+			continue
+		}
+		if body[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+// expandEnvValue replaces variable references like $NAME, ${NAME} or ${NAME:-default} in value and, if
+// escapes is true, resolves backslash escape sequences like in double-quoted values
+func expandEnvValue(value string, lookup func(string) (string, bool), escapes bool) (string, error) {
+	var result strings.Builder
+	for i := 0; i < len(value); i++ {
+		character := value[i]
+		if escapes && character == '\\' && i+1 < len(value) {
+			switch value[i+1] {
+			case 'n':
+				result.WriteByte('\n')
+			case 'r':
+				result.WriteByte('\r')
+			case 't':
+				result.WriteByte('\t')
+			case '\\', '"', '$':
+				result.WriteByte(value[i+1])
+			default:
+				result.WriteByte(character)
+				continue
+			}
+			i++
+			continue
+		}
+		if character != '$' || i+1 >= len(value) {
+			result.WriteByte(character)
+			continue
+		}
+
+		next := value[i+1]
+		switch {
+		case next == '$':
+			result.WriteByte('$')
+			i++
+		case next == '{':
+			end := findClosingBrace(value, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable reference in %q", value)
+			}
+			expanded, err := expandBracedReference(value[i+2:end], lookup)
+			if err != nil {
+				return "", err
+			}
+			result.WriteString(expanded)
+			i = end
+		case isEnvNameCharacter(next, true):
+			end := i + 1
+			for end < len(value) && isEnvNameCharacter(value[end], false) {
+				end++
+			}
+			expanded, _ := lookup(value[i+1 : end])
+			result.WriteString(expanded)
+			i = end - 1
+		default:
+			result.WriteByte(character)
+		}
+	}
+	return result.String(), nil
+}
+
+func findClosingBrace(value string, start int) int {
+	depth := 1
+	for i := start; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func expandBracedReference(reference string, lookup func(string) (string, bool)) (string, error) {
+	nameLength := 0
+	for nameLength < len(reference) && isEnvNameCharacter(reference[nameLength], nameLength == 0) {
+		nameLength++
+	}
+	name := reference[:nameLength]
+	if len(name) == 0 {
+		return "", fmt.Errorf("invalid variable reference ${%v}", reference)
+	}
+
+	value, isSet := lookup(name)
+	operator := reference[nameLength:]
+	if len(operator) == 0 {
+		return value, nil
+	}
+
+	checkEmpty := strings.HasPrefix(operator, ":")
+	operator = strings.TrimPrefix(operator, ":")
+	if len(operator) == 0 {
+		return "", fmt.Errorf("invalid variable reference ${%v}", reference)
+	}
+	operand, err := expandEnvValue(operator[1:], lookup, false)
+	if err != nil {
+		return "", err
+	}
+	isUsable := isSet && (!checkEmpty || len(value) > 0)
+
+	switch operator[0] {
+	case '-':
+		if !isUsable {
+			return operand, nil
+		}
+		return value, nil
+	case '?':
+		if !isUsable {
+			if len(operand) == 0 {
+				operand = "required variable " + name + " is missing a value"
+			}
+			return "", fmt.Errorf("%v", operand)
+		}
+		return value, nil
+	case '+':
+		if isUsable {
+			return operand, nil
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("invalid variable reference ${%v}", reference)
+}
+
+func isEnvNameCharacter(character byte, first bool) bool {
+	if character == '_' || (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z') {
+		return true
+	}
+	return !first && character >= '0' && character <= '9'
+}
+
+// SetConfigValue sets the given key to value in the given environment file. The effective definition is
+// replaced in place, otherwise the definition is appended, so that comments and order are preserved.
+func SetConfigValue(pathAndFilename string, key string, value string) error {
+	if !envNamePattern.MatchString(key) {
+		return fmt.Errorf("invalid variable name %q", key)
+	}
+
+	source, err := os.ReadFile(pathAndFilename)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	content := strings.ReplaceAll(string(source), "\r\n", "\n")
+	definitions, err := parseEnvFile(pathAndFilename, content, func(string) (string, bool) { return "", false })
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(content, "\n")
+	definitionLine := key + "=" + formatEnvValue(value)
+	for i := len(definitions) - 1; i >= 0; i-- {
+		definition := definitions[i]
+		if definition.Key != key {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(lines[definition.Line-1]), "export") {
+			definitionLine = "export " + definitionLine
+		}
+		lines = append(lines[:definition.Line-1], append([]string{definitionLine}, lines[definition.endLine:]...)...)
+		return os.WriteFile(pathAndFilename, []byte(strings.Join(lines, "\n")), 0644)
+	}
+
+	if len(content) > 0 && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return os.WriteFile(pathAndFilename, []byte(content+definitionLine+"\n"), 0644)
+}
+
+// formatEnvValue returns value as it needs to be written into an environment file, quoted if needed
+func formatEnvValue(value string) string {
+	if envPlainValuePattern.MatchString(value) {
+		return value
+	}
+	replacer := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "$", "$$", "\n", "\\n", "\r", "\\r", "\t", "\\t")
+	return "\"" + replacer.Replace(value) + "\""
+}
@@ -19,7 +19,6 @@ import (
 	"os"
 	"path"
 	"path/filepath"
-	"strings"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -48,35 +47,3 @@ func detectProjectRootPath(currentPath string) (projectRootPath string, err erro
 
 	return detectProjectRootPath(path.Dir(projectRootPath))
 }
-
-func loadLocalBeachEnvironment(projectRootPath string) (err error) {
-	envFilenames := []string{".localbeach.dist.env", ".localbeach.env", ".env"}
-	envPathAndFilename := ""
-
-	for _, envFilename := range envFilenames {
-		envPathAndFilename = filepath.Join(projectRootPath, envFilename)
-
-		if _, err := os.Stat(envPathAndFilename); err == nil {
-
-			source, err := os.ReadFile(envPathAndFilename)
-			if err != nil {
-				return errors.New("failed loading environment file " + envPathAndFilename + ": " + err.Error())
-			}
-
-			for _, line := range strings.Split(string(source), "\n") {
-				trimmedLine := strings.TrimSpace(line)
-				if len(trimmedLine) > 0 && !strings.HasPrefix(trimmedLine, "#") {
-					nameAndValue := strings.SplitN(trimmedLine, "=", 2)
-					if len(nameAndValue) != 2 {
-						return errors.New("failed parsing environment variable " + nameAndValue[0])
-					}
-					if err := os.Setenv(nameAndValue[0], nameAndValue[1]); err != nil {
-						return errors.New("failed setting environment variable " + nameAndValue[0])
-					}
-				}
-			}
-		}
-	}
-
-	return
-}
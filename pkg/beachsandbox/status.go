@@ -0,0 +1,147 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beachsandbox
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/flownative/localbeach/pkg/exec"
+)
+
+// Status describes the containers of a sandbox and of the shared Local Beach services
+type Status struct {
+	ProjectName     string            `json:"projectName" yaml:"projectName"`
+	ProjectRootPath string            `json:"projectRootPath" yaml:"projectRootPath"`
+	URLs            []string          `json:"urls" yaml:"urls"`
+	Containers      []ContainerStatus `json:"containers" yaml:"containers"`
+	SharedServices  []ContainerStatus `json:"sharedServices" yaml:"sharedServices"`
+}
+
+// ContainerStatus describes the state of a single container
+type ContainerStatus struct {
+	Name    string        `json:"name" yaml:"name"`
+	Service string        `json:"service" yaml:"service"`
+	Image   string        `json:"image" yaml:"image"`
+	State   string        `json:"state" yaml:"state"`
+	Health  string        `json:"health,omitempty" yaml:"health,omitempty"`
+	Ports   []PortMapping `json:"ports" yaml:"ports"`
+}
+
+// PortMapping describes a container port and the host port it is published on, if any
+type PortMapping struct {
+	HostIP        string `json:"hostIp,omitempty" yaml:"hostIp,omitempty"`
+	HostPort      int    `json:"hostPort,omitempty" yaml:"hostPort,omitempty"`
+	ContainerPort int    `json:"containerPort" yaml:"containerPort"`
+	Protocol      string `json:"protocol" yaml:"protocol"`
+}
+
+// IsRunning returns true if the container is running
+func (containerStatus ContainerStatus) IsRunning() bool {
+	return containerStatus.State == "running"
+}
+
+type composeContainer struct {
+	Name       string `json:"Name"`
+	Service    string `json:"Service"`
+	Image      string `json:"Image"`
+	State      string `json:"State"`
+	Health     string `json:"Health"`
+	Publishers []struct {
+		URL           string `json:"URL"`
+		TargetPort    int    `json:"TargetPort"`
+		PublishedPort int    `json:"PublishedPort"`
+		Protocol      string `json:"Protocol"`
+	} `json:"Publishers"`
+}
+
+// GetStatus returns the status of the sandbox's containers and the shared services defined in the given
+// Docker Compose file, using the command line tool of the given container runtime. Shared services are omitted
+// if that file does not exist.
+func (sandbox *BeachSandbox) GetStatus(runtimeCommand string, sharedServicesComposeFilePath string, environment []string) (*Status, error) {
+	status := &Status{
+		ProjectName:     sandbox.ProjectName,
+		ProjectRootPath: sandbox.ProjectRootPath,
+		Containers:      []ContainerStatus{},
+		SharedServices:  []ContainerStatus{},
+	}
+	for _, virtualHost := range sandbox.Config.VirtualHosts {
+		status.URLs = append(status.URLs, "http://"+virtualHost)
+	}
+
+	var err error
+	status.Containers, err = getComposeContainerStatus(runtimeCommand, sandbox.DockerComposeFilePath, environment)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(sharedServicesComposeFilePath); err == nil {
+		status.SharedServices, err = getComposeContainerStatus(runtimeCommand, sharedServicesComposeFilePath, environment)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func getComposeContainerStatus(runtimeCommand string, composeFilePath string, environment []string) ([]ContainerStatus, error) {
+	output, err := exec.RunCommandWithEnv(runtimeCommand, []string{"compose", "-f", composeFilePath, "ps", "--all", "--format", "json"}, environment)
+	if err != nil {
+		return nil, errors.New("failed retrieving container status: " + strings.TrimSpace(output))
+	}
+
+	var composeContainers []composeContainer
+	output = strings.TrimSpace(output)
+	if strings.HasPrefix(output, "[") {
+		// Docker Compose before version 2.21 returns a JSON array instead of one object per line
+		if err := json.Unmarshal([]byte(output), &composeContainers); err != nil {
+			return nil, errors.New("failed parsing container status: " + err.Error())
+		}
+	} else {
+		for _, line := range strings.Split(output, "\n") {
+			if len(strings.TrimSpace(line)) == 0 {
+				continue
+			}
+			var composeContainer composeContainer
+			if err := json.Unmarshal([]byte(line), &composeContainer); err != nil {
+				return nil, errors.New("failed parsing container status: " + err.Error())
+			}
+			composeContainers = append(composeContainers, composeContainer)
+		}
+	}
+
+	containers := []ContainerStatus{}
+	for _, composeContainer := range composeContainers {
+		container := ContainerStatus{
+			Name:    composeContainer.Name,
+			Service: composeContainer.Service,
+			Image:   composeContainer.Image,
+			State:   composeContainer.State,
+			Health:  composeContainer.Health,
+			Ports:   []PortMapping{},
+		}
+		for _, publisher := range composeContainer.Publishers {
+			container.Ports = append(container.Ports, PortMapping{
+				HostIP:        publisher.URL,
+				HostPort:      publisher.PublishedPort,
+				ContainerPort: publisher.TargetPort,
+				Protocol:      publisher.Protocol,
+			})
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
@@ -0,0 +1,334 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificates
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoAuthorityFound is returned if the certificate authority has not been created yet
+var ErrNoAuthorityFound = errors.New("could not find the Local Beach certificate authority – run \"beach setup-https\" to create it")
+
+const authorityCertificateFilename = "rootCA.pem"
+const authorityKeyFilename = "rootCA-key.pem"
+const authorityValidity = 10 * 365 * 24 * time.Hour
+
+// certificateValidity is the maximum validity accepted by browsers for certificates of private authorities
+const certificateValidity = 825 * 24 * time.Hour
+
+// Authority is the certificate authority which signs the certificates of Local Beach
+type Authority struct {
+	Certificate                *x509.Certificate
+	CertificatePathAndFilename string
+	privateKey                 crypto.Signer
+}
+
+// Certificate contains information about a certificate found in the certificates directory
+type Certificate struct {
+	Name              string
+	PathAndFilename   string
+	Hosts             []string
+	NotAfter          time.Time
+	IssuedByAuthority bool
+}
+
+// LoadAuthority loads the certificate authority from the given directory
+func LoadAuthority(authorityPath string) (*Authority, error) {
+	certificatePathAndFilename := filepath.Join(authorityPath, authorityCertificateFilename)
+	keyPathAndFilename := filepath.Join(authorityPath, authorityKeyFilename)
+
+	certificatePEM, err := os.ReadFile(certificatePathAndFilename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoAuthorityFound
+	} else if err != nil {
+		return nil, err
+	}
+	certificate, err := parseCertificate(certificatePEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing %v: %v", certificatePathAndFilename, err)
+	}
+
+	keyPEM, err := os.ReadFile(keyPathAndFilename)
+	if err != nil {
+		return nil, err
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, fmt.Errorf("failed parsing %v", keyPathAndFilename)
+	}
+	privateKey, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing %v: %v", keyPathAndFilename, err)
+	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key in %v", keyPathAndFilename)
+	}
+
+	return &Authority{Certificate: certificate, CertificatePathAndFilename: certificatePathAndFilename, privateKey: signer}, nil
+}
+
+// LoadOrCreateAuthority loads the certificate authority from the given directory and creates a new one if none
+// exists yet. The authority is not restricted to certain domains, because projects may use any host name.
+func LoadOrCreateAuthority(authorityPath string) (authority *Authority, created bool, err error) {
+	authority, err = LoadAuthority(authorityPath)
+	if !errors.Is(err, ErrNoAuthorityFound) {
+		return authority, false, err
+	}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, false, err
+	}
+	serialNumber, err := generateSerialNumber()
+	if err != nil {
+		return nil, false, err
+	}
+
+	commonName := "Local Beach CA"
+	if currentUser, err := user.Current(); err == nil {
+		hostname, _ := os.Hostname()
+		commonName += " " + currentUser.Username + "@" + hostname
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{Organization: []string{"Local Beach development CA"}, CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(authorityValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	certificateDER, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Only the current user may access the private key of the authority
+	if err := os.MkdirAll(authorityPath, 0700); err != nil {
+		return nil, false, err
+	}
+	err = writeCertificateAndKey(authorityPath, authorityCertificateFilename, authorityKeyFilename, certificateDER, privateKey)
+	if err != nil {
+		return nil, false, err
+	}
+
+	authority, err = LoadAuthority(authorityPath)
+	return authority, err == nil, err
+}
+
+// MigrateAuthority moves the certificate authority from the given previous directory to the given directory,
+// unless an authority exists there already, and restricts the access to the directory to the current user
+func MigrateAuthority(previousAuthorityPath string, authorityPath string) error {
+	if _, err := os.Stat(filepath.Join(authorityPath, authorityCertificateFilename)); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(previousAuthorityPath, authorityCertificateFilename)); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err := os.MkdirAll(authorityPath, 0700); err != nil {
+		return err
+	}
+	if err := os.Chmod(authorityPath, 0700); err != nil {
+		return err
+	}
+	// The key is moved last, so that an interrupted migration is completed the next time
+	for _, filename := range []string{authorityCertificateFilename, authorityKeyFilename} {
+		content, err := os.ReadFile(filepath.Join(previousAuthorityPath, filename))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(authorityPath, filename), content, 0600); err != nil {
+			return err
+		}
+	}
+	for _, filename := range []string{authorityKeyFilename, authorityCertificateFilename} {
+		if err := os.Remove(filepath.Join(previousAuthorityPath, filename)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	_ = os.Remove(previousAuthorityPath)
+	return nil
+}
+
+// Issue creates a certificate for the given hosts, signed by the authority, and stores it as <name>.crt
+// and <name>.key in the certificates directory
+func (authority *Authority) Issue(certificatesPath string, name string, hosts []string) error {
+	if len(hosts) == 0 {
+		return errors.New("cannot issue a certificate without hosts")
+	}
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serialNumber, err := generateSerialNumber()
+	if err != nil {
+		return err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{Organization: []string{"Local Beach development certificate"}, CommonName: hosts[0]},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(certificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	certificateDER, err := x509.CreateCertificate(rand.Reader, template, authority.Certificate, privateKey.Public(), authority.privateKey)
+	if err != nil {
+		return err
+	}
+	return writeCertificateAndKey(certificatesPath, name+".crt", name+".key", certificateDER, privateKey)
+}
+
+// List returns the certificates found in the given certificates directory. If an authority is given,
+// certificates signed by it are marked as such.
+func List(certificatesPath string, authority *Authority) ([]Certificate, error) {
+	pathAndFilenames, err := filepath.Glob(filepath.Join(certificatesPath, "*.crt"))
+	if err != nil {
+		return nil, err
+	}
+
+	var certificates []Certificate
+	for _, pathAndFilename := range pathAndFilenames {
+		certificatePEM, err := os.ReadFile(pathAndFilename)
+		if err != nil {
+			return nil, err
+		}
+		parsedCertificate, err := parseCertificate(certificatePEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing %v: %v", pathAndFilename, err)
+		}
+
+		certificate := Certificate{
+			Name:            strings.TrimSuffix(filepath.Base(pathAndFilename), ".crt"),
+			PathAndFilename: pathAndFilename,
+			Hosts:           parsedCertificate.DNSNames,
+			NotAfter:        parsedCertificate.NotAfter,
+		}
+		for _, ip := range parsedCertificate.IPAddresses {
+			certificate.Hosts = append(certificate.Hosts, ip.String())
+		}
+		if authority != nil {
+			certificate.IssuedByAuthority = parsedCertificate.CheckSignatureFrom(authority.Certificate) == nil
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	sort.Slice(certificates, func(i, j int) bool {
+		return certificates[i].Name < certificates[j].Name
+	})
+	return certificates, nil
+}
+
+// Covers checks if the certificate is valid for the given host and not expired
+func (certificate Certificate) Covers(host string) bool {
+	if time.Now().After(certificate.NotAfter) {
+		return false
+	}
+	host = strings.ToLower(host)
+	for _, certificateHost := range certificate.Hosts {
+		certificateHost = strings.ToLower(certificateHost)
+		if certificateHost == host {
+			return true
+		}
+		if strings.HasPrefix(certificateHost, "*.") {
+			label, domain, found := strings.Cut(host, ".")
+			if found && len(label) > 0 && domain == certificateHost[2:] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsHostCovered checks if the reverse proxy finds a valid certificate for the given host among the given
+// certificates: it uses a certificate named after the host, after its parent domain or the default one
+func IsHostCovered(certificates []Certificate, host string) bool {
+	candidateNames := []string{host, "default"}
+	if _, parentDomain, found := strings.Cut(host, "."); found {
+		candidateNames = append(candidateNames, parentDomain)
+	}
+
+	for _, certificate := range certificates {
+		for _, candidateName := range candidateNames {
+			if certificate.Name == candidateName && certificate.Covers(host) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FilenameForHost returns the name of the certificate files for the given host, as expected by the
+// reverse proxy: wildcard certificates are named after the domain they cover
+func FilenameForHost(host string) string {
+	return strings.TrimPrefix(host, "*.")
+}
+
+func parseCertificate(certificatePEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certificatePEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func writeCertificateAndKey(directory string, certificateFilename string, keyFilename string, certificateDER []byte, privateKey crypto.PrivateKey) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(directory, keyFilename), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(directory, certificateFilename), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateDER}), 0644)
+}
+
+func generateSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
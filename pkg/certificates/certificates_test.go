@@ -0,0 +1,145 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificates
+
+import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIssueCertificatesForAnyHost(t *testing.T) {
+	authorityPath := filepath.Join(t.TempDir(), "CertificateAuthority")
+	certificatesPath := t.TempDir()
+
+	authority, created, err := LoadOrCreateAuthority(authorityPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created {
+		t.Fatal("expected a new certificate authority to be created")
+	}
+	info, err := os.Stat(authorityPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("expected the authority directory to have mode 0700, got %v", info.Mode().Perm())
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(authority.Certificate)
+
+	tests := []struct {
+		name  string
+		hosts []string
+	}{
+		{name: "wildcard", hosts: []string{"*.localbeach.net"}},
+		{name: "outside-domain", hosts: []string{"acme.test", "www.acme-corporation.com"}},
+		{name: "localhost", hosts: []string{"localhost", "127.0.0.1"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := authority.Issue(certificatesPath, test.name, test.hosts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			certificatePEM, err := os.ReadFile(filepath.Join(certificatesPath, test.name+".crt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			certificate, err := parseCertificate(certificatePEM)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, host := range test.hosts {
+				verifiedHost := host
+				if host == "*.localbeach.net" {
+					verifiedHost = "acme.localbeach.net"
+				}
+				_, err := certificate.Verify(x509.VerifyOptions{DNSName: verifiedHost, Roots: roots})
+				if err != nil {
+					t.Errorf("expected the certificate to be valid for %v: %v", verifiedHost, err)
+				}
+			}
+		})
+	}
+
+	certificateList, err := List(certificatesPath, authority)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(certificateList) != len(tests) {
+		t.Fatalf("expected %d certificates, got %d", len(tests), len(certificateList))
+	}
+	for _, certificate := range certificateList {
+		if !certificate.IssuedByAuthority {
+			t.Errorf("expected %v to be issued by the authority", certificate.Name)
+		}
+	}
+	if err := authority.Issue(certificatesPath, FilenameForHost("acme.test"), []string{"acme.test"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	certificateList, err = List(certificatesPath, authority)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !IsHostCovered(certificateList, "acme.test") {
+		t.Error("expected acme.test to be covered")
+	}
+
+	loadedAuthority, created, err := LoadOrCreateAuthority(authorityPath)
+	if err != nil || created {
+		t.Fatalf("expected the existing authority to be loaded, got created %v and error %v", created, err)
+	}
+	if !loadedAuthority.Certificate.Equal(authority.Certificate) {
+		t.Error("expected the same authority certificate to be loaded")
+	}
+}
+
+func TestMigrateAuthority(t *testing.T) {
+	previousAuthorityPath := filepath.Join(t.TempDir(), "Certificates", "CA")
+	authorityPath := filepath.Join(t.TempDir(), "CertificateAuthority")
+
+	authority, _, err := LoadOrCreateAuthority(previousAuthorityPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := MigrateAuthority(previousAuthorityPath, authorityPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(previousAuthorityPath); !os.IsNotExist(err) {
+		t.Errorf("expected %v to be removed, got %v", previousAuthorityPath, err)
+	}
+
+	migratedAuthority, err := LoadAuthority(authorityPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !migratedAuthority.Certificate.Equal(authority.Certificate) {
+		t.Error("expected the migrated authority to be the same")
+	}
+	info, err := os.Stat(filepath.Join(authorityPath, authorityKeyFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected the key to have mode 0600, got %v", info.Mode().Perm())
+	}
+
+	if err := MigrateAuthority(previousAuthorityPath, authorityPath); err != nil {
+		t.Errorf("expected a second migration to do nothing, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// 💡 See https://golang.org/cmd/go/#hdr-Build_constraints for explanation of build constraints
+
+//go:build darwin
+
+package certificates
+
+import (
+	"github.com/flownative/localbeach/pkg/exec"
+)
+
+// Install adds the certificate of the authority to the system keychain, the user will be asked for the password
+func (authority *Authority) Install() error {
+	return exec.RunInteractiveCommand("sudo", []string{
+		"security", "add-trusted-cert", "-d", "-r", "trustRoot", "-k", "/Library/Keychains/System.keychain", authority.CertificatePathAndFilename,
+	})
+}
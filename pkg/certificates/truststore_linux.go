@@ -0,0 +1,47 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// 💡 See https://golang.org/cmd/go/#hdr-Build_constraints for explanation of build constraints
+
+//go:build linux
+
+package certificates
+
+import (
+	"errors"
+	"os"
+
+	"github.com/flownative/localbeach/pkg/exec"
+)
+
+// Install adds the certificate of the authority to the system trust store, the user will be asked for the password
+func (authority *Authority) Install() error {
+	if _, err := os.Stat("/usr/local/share/ca-certificates"); err == nil {
+		err := exec.RunInteractiveCommand("sudo", []string{"cp", authority.CertificatePathAndFilename, "/usr/local/share/ca-certificates/localbeach-rootCA.crt"})
+		if err != nil {
+			return err
+		}
+		return exec.RunInteractiveCommand("sudo", []string{"update-ca-certificates"})
+	}
+
+	if _, err := os.Stat("/etc/pki/ca-trust/source/anchors"); err == nil {
+		err := exec.RunInteractiveCommand("sudo", []string{"cp", authority.CertificatePathAndFilename, "/etc/pki/ca-trust/source/anchors/localbeach-rootCA.pem"})
+		if err != nil {
+			return err
+		}
+		return exec.RunInteractiveCommand("sudo", []string{"update-ca-trust", "extract"})
+	}
+
+	return errors.New("could not find a supported system trust store, please install " + authority.CertificatePathAndFilename + " manually")
+}
@@ -0,0 +1,257 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration contains the global settings of Local Beach
+type Configuration struct {
+	Domain   string                `yaml:"domain"`
+	Ports    PortsConfiguration    `yaml:"ports"`
+	Proxy    ProxyConfiguration    `yaml:"proxy"`
+	Database DatabaseConfiguration `yaml:"database"`
+	Postgres PostgresConfiguration `yaml:"postgres"`
+	DNS      DNSConfiguration      `yaml:"dns"`
+	Paths    PathsConfiguration    `yaml:"paths"`
+	Runtime  RuntimeConfiguration  `yaml:"runtime"`
+}
+
+// PortsConfiguration contains the ports published on the host
+type PortsConfiguration struct {
+	HTTP     int `yaml:"http"`
+	HTTPS    int `yaml:"https"`
+	Database int `yaml:"database"`
+	Postgres int `yaml:"postgres"`
+}
+
+// ProxyConfiguration contains the settings of the shared reverse proxy
+type ProxyConfiguration struct {
+	Image string `yaml:"image"`
+}
+
+// DatabaseConfiguration contains the settings of the shared database server, StartupTimeout is the time to wait
+// for the server to become healthy
+type DatabaseConfiguration struct {
+	Image          string        `yaml:"image"`
+	RootPassword   string        `yaml:"rootPassword"`
+	StartupTimeout time.Duration `yaml:"startupTimeout"`
+}
+
+// PostgresConfiguration contains the settings of the optional shared PostgreSQL server, which is only started
+// if a project uses it
+type PostgresConfiguration struct {
+	Image    string `yaml:"image"`
+	Password string `yaml:"password"`
+}
+
+// DNSConfiguration contains the settings of the optional DNS resolver, which resolves the domain to 127.0.0.1
+// and forwards all other queries to the upstream servers
+type DNSConfiguration struct {
+	Enabled  bool     `yaml:"enabled"`
+	Image    string   `yaml:"image"`
+	Port     int      `yaml:"port"`
+	Upstream []string `yaml:"upstream"`
+}
+
+// PathsConfiguration contains optional overrides for the data directories
+type PathsConfiguration struct {
+	Certificates string `yaml:"certificates"`
+	Database     string `yaml:"database"`
+	Postgres     string `yaml:"postgres"`
+}
+
+// RuntimeConfiguration selects the container runtime, which is detected automatically if no name is given.
+// The socket path is only needed if the API socket of the runtime is not at its default location.
+type RuntimeConfiguration struct {
+	Name       string `yaml:"name"`
+	SocketPath string `yaml:"socketPath"`
+}
+
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)*[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// passwordPattern matches passwords which can be inserted into the Docker Compose files and the shell commands of
+// the health checks without quoting
+var passwordPattern = regexp.MustCompile(`^[A-Za-z0-9_.,:@%+=/-]+$`)
+
+// Current is the active configuration, containing the defaults until Load is called
+var Current = Default()
+
+// Default returns the configuration used if no configuration file exists
+func Default() *Configuration {
+	return &Configuration{
+		Domain: "localbeach.net",
+		Ports: PortsConfiguration{
+			HTTP:     80,
+			HTTPS:    443,
+			Database: 3307,
+			Postgres: 5433,
+		},
+		Proxy: ProxyConfiguration{
+			Image: "flownative/localbeach-nginx-proxy:0.5.0",
+		},
+		Database: DatabaseConfiguration{
+			Image:          "mariadb:10.11",
+			RootPassword:   "password",
+			StartupTimeout: 30 * time.Second,
+		},
+		Postgres: PostgresConfiguration{
+			Image:    "postgres:16",
+			Password: "password",
+		},
+		DNS: DNSConfiguration{
+			Image:    "4km3/dnsmasq:2.90-r3",
+			Port:     53,
+			Upstream: []string{"1.1.1.1", "8.8.8.8"},
+		},
+	}
+}
+
+// Load reads the given configuration file into Current, settings missing in the file keep their defaults
+func Load(pathAndFilename string) error {
+	configuration := Default()
+
+	content, err := os.ReadFile(pathAndFilename)
+	if errors.Is(err, os.ErrNotExist) {
+		Current = configuration
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed loading configuration file %v: %v", pathAndFilename, err)
+	}
+
+	// The YAML decoder rejects bare numbers for durations as well, but with a less helpful message
+	if err := checkDurationUnits(content, "database", "startupTimeout"); err != nil {
+		return fmt.Errorf("invalid configuration in %v: %v", pathAndFilename, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(configuration); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed parsing configuration file %v: %v", pathAndFilename, err)
+	}
+
+	configuration.Paths.Certificates = expandHomeDirectory(configuration.Paths.Certificates)
+	configuration.Paths.Database = expandHomeDirectory(configuration.Paths.Database)
+	configuration.Paths.Postgres = expandHomeDirectory(configuration.Paths.Postgres)
+	configuration.Runtime.SocketPath = expandHomeDirectory(configuration.Runtime.SocketPath)
+
+	if err := configuration.validate(); err != nil {
+		return fmt.Errorf("invalid configuration in %v: %v", pathAndFilename, err)
+	}
+
+	Current = configuration
+	return nil
+}
+
+// IsValidDomain returns true if the given domain can be used for the hosts of projects
+func IsValidDomain(domain string) bool {
+	return domainPattern.MatchString(domain)
+}
+
+func (configuration *Configuration) validate() error {
+	if !IsValidDomain(configuration.Domain) {
+		return fmt.Errorf("domain must be a valid domain name, %q given", configuration.Domain)
+	}
+	ports := map[string]int{
+		"ports.http":     configuration.Ports.HTTP,
+		"ports.https":    configuration.Ports.HTTPS,
+		"ports.database": configuration.Ports.Database,
+		"ports.postgres": configuration.Ports.Postgres,
+		"dns.port":       configuration.DNS.Port,
+	}
+	for name, port := range ports {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("%v must be a port number between 1 and 65535, %d given", name, port)
+		}
+	}
+	if len(configuration.Proxy.Image) == 0 {
+		return errors.New("proxy.image must not be empty")
+	}
+	if len(configuration.Database.Image) == 0 {
+		return errors.New("database.image must not be empty")
+	}
+	if len(configuration.Postgres.Image) == 0 {
+		return errors.New("postgres.image must not be empty")
+	}
+	if !passwordPattern.MatchString(configuration.Postgres.Password) {
+		return errors.New("postgres.password must not be empty and may only contain letters, digits and _.,:@%+=/-")
+	}
+	if configuration.DNS.Enabled && len(configuration.DNS.Image) == 0 {
+		return errors.New("dns.image must not be empty")
+	}
+	if configuration.DNS.Enabled && len(configuration.DNS.Upstream) == 0 {
+		return errors.New("dns.upstream must contain at least one server")
+	}
+	if !passwordPattern.MatchString(configuration.Database.RootPassword) {
+		return errors.New("database.rootPassword must not be empty and may only contain letters, digits and _.,:@%+=/-")
+	}
+	if configuration.Database.StartupTimeout <= 0 {
+		return fmt.Errorf("database.startupTimeout must be a positive duration like 2m, %v given", configuration.Database.StartupTimeout)
+	}
+	switch configuration.Runtime.Name {
+	case "", "docker", "podman", "nerdctl":
+	default:
+		return fmt.Errorf("runtime.name must be one of docker, podman or nerdctl, %q given", configuration.Runtime.Name)
+	}
+	return nil
+}
+
+// checkDurationUnits returns an error if the duration at the given path of keys is a bare number without a unit
+func checkDurationUnits(content []byte, keys ...string) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil || len(document.Content) == 0 {
+		// Syntax errors are reported by the decoder
+		return nil
+	}
+	node := document.Content[0]
+	for _, key := range keys {
+		var value *yaml.Node
+		for i := 0; node.Kind == yaml.MappingNode && i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				value = node.Content[i+1]
+			}
+		}
+		if value == nil {
+			return nil
+		}
+		node = value
+	}
+	if node.Tag == "!!int" || node.Tag == "!!float" {
+		return fmt.Errorf("%v must be a duration with a unit like 2m or 90s, %v given", strings.Join(keys, "."), node.Value)
+	}
+	return nil
+}
+
+func expandHomeDirectory(pathAndFilename string) string {
+	if !strings.HasPrefix(pathAndFilename, "~/") {
+		return pathAndFilename
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return pathAndFilename
+	}
+	return filepath.Join(homeDir, pathAndFilename[2:])
+}
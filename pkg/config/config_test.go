@@ -0,0 +1,112 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	previousConfiguration := Current
+	t.Cleanup(func() {
+		Current = previousConfiguration
+	})
+
+	tests := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{name: "empty file", content: ""},
+		{name: "duration with unit", content: "database:\n  startupTimeout: 2m\n"},
+		{name: "password with allowed special characters", content: "database:\n  rootPassword: \"s3cr3t_.,:@%+=/-\"\n"},
+		{name: "invalid syntax", content: "database: [", expectedError: "failed parsing configuration file"},
+		{name: "unknown setting", content: "database:\n  password: secret\n", expectedError: "field password not found"},
+		{name: "duration without unit", content: "database:\n  startupTimeout: 30\n", expectedError: "database.startupTimeout must be a duration with a unit"},
+		{name: "negative duration", content: "database:\n  startupTimeout: -1m\n", expectedError: "database.startupTimeout must be a positive duration"},
+		{name: "invalid domain", content: "domain: Beach_Test\n", expectedError: "domain must be a valid domain name"},
+		{name: "root password with space", content: "database:\n  rootPassword: \"secret password\"\n", expectedError: "database.rootPassword must not be empty"},
+		{name: "root password with quote", content: "database:\n  rootPassword: \"secret\\\"\"\n", expectedError: "database.rootPassword must not be empty"},
+		{name: "root password with dollar sign", content: "database:\n  rootPassword: \"$ecret\"\n", expectedError: "database.rootPassword must not be empty"},
+		{name: "empty postgres password", content: "postgres:\n  password: \"\"\n", expectedError: "postgres.password must not be empty"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pathAndFilename := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(pathAndFilename, []byte(test.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			err := Load(pathAndFilename)
+			if len(test.expectedError) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Errorf("expected an error containing %q, got %v", test.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestLoadKeepsDefaults(t *testing.T) {
+	previousConfiguration := Current
+	t.Cleanup(func() {
+		Current = previousConfiguration
+	})
+
+	pathAndFilename := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(pathAndFilename, []byte("database:\n  startupTimeout: 90s\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Load(pathAndFilename); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if Current.Database.StartupTimeout != 90*time.Second {
+		t.Errorf("expected a startup timeout of 90s, got %v", Current.Database.StartupTimeout)
+	}
+	if Current.Database.Image != Default().Database.Image {
+		t.Errorf("expected the default database image, got %v", Current.Database.Image)
+	}
+}
+
+func TestIsValidDomain(t *testing.T) {
+	tests := map[string]bool{
+		"localbeach.net":      true,
+		"beach.test":          true,
+		"localhost":           true,
+		"my-project.beach.io": true,
+		"":                    false,
+		"Beach.test":          false,
+		"beach_test.net":      false,
+		"-beach.test":         false,
+		"beach-.test":         false,
+		"beach..test":         false,
+		"beach.test.":         false,
+		"*.beach.test":        false,
+		"beach.test/path":     false,
+		"beach test":          false,
+	}
+	for domain, expected := range tests {
+		if IsValidDomain(domain) != expected {
+			t.Errorf("expected IsValidDomain(%q) to return %v", domain, expected)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	osexec "os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CLIClient controls containers through a Docker compatible command line tool, for runtimes which provide no
+// API socket
+type CLIClient struct {
+	command string
+}
+
+type cliContainerSummary struct {
+	ID       string `json:"ID"`
+	Names    string `json:"Names"`
+	Image    string `json:"Image"`
+	State    string `json:"State"`
+	Status   string `json:"Status"`
+	Labels   string `json:"Labels"`
+	Networks string `json:"Networks"`
+}
+
+// NewCLIClient returns a client using the given command line tool, for example "nerdctl"
+func NewCLIClient(command string) *CLIClient {
+	return &CLIClient{command: command}
+}
+
+// List returns the containers matching the given options
+func (client *CLIClient) List(ctx context.Context, options ListOptions) ([]Container, error) {
+	args := []string{"ps", "--no-trunc", "--format", "{{json .}}"}
+	if options.All {
+		args = append(args, "--all")
+	}
+	if len(options.Name) > 0 {
+		args = append(args, "--filter", "name=^"+options.Name+"$")
+	}
+	if len(options.Network) > 0 {
+		args = append(args, "--filter", "network="+options.Network)
+	}
+	for key, value := range options.Labels {
+		args = append(args, "--filter", "label="+key+"="+value)
+	}
+
+	output, err := client.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	containers := []Container{}
+	for _, line := range strings.Split(output, "\n") {
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+		var summary cliContainerSummary
+		if err := json.Unmarshal([]byte(line), &summary); err != nil {
+			return nil, errors.New("failed parsing container list: " + err.Error())
+		}
+
+		container := Container{
+			ID:     summary.ID,
+			Name:   strings.Split(summary.Names, ",")[0],
+			Image:  summary.Image,
+			State:  summary.State,
+			Health: parseHealthFromStatus(summary.Status),
+			Labels: parseLabels(summary.Labels),
+		}
+		if len(container.State) == 0 {
+			container.State = "exited"
+			if strings.HasPrefix(summary.Status, "Up") {
+				container.State = "running"
+			}
+		}
+		if len(summary.Networks) > 0 {
+			container.Networks = strings.Split(summary.Networks, ",")
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// Inspect returns the container with the given name or id, or ErrNotFound
+func (client *CLIClient) Inspect(ctx context.Context, nameOrID string) (*Container, error) {
+	output, err := client.run(ctx, "container", "inspect", nameOrID)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no such") || strings.Contains(strings.ToLower(err.Error()), "not found") {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, nameOrID)
+		}
+		return nil, err
+	}
+
+	var details []engineContainerDetails
+	if err := json.Unmarshal([]byte(output), &details); err != nil {
+		return nil, errors.New("failed parsing container details: " + err.Error())
+	}
+	if len(details) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, nameOrID)
+	}
+
+	container := &Container{
+		ID:       details[0].ID,
+		Name:     strings.TrimPrefix(details[0].Name, "/"),
+		Image:    details[0].Config.Image,
+		State:    details[0].State.Status,
+		Labels:   details[0].Config.Labels,
+		Networks: sortedKeys(details[0].NetworkSettings.Networks),
+	}
+	if details[0].State.Health != nil {
+		container.Health = details[0].State.Health.Status
+	}
+	return container, nil
+}
+
+// Health returns the health status of the given container, which is HealthNone if it has no health check
+func (client *CLIClient) Health(ctx context.Context, nameOrID string) (string, error) {
+	container, err := client.Inspect(ctx, nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return container.Health, nil
+}
+
+// Exec runs a command in the given container and returns its exit code
+func (client *CLIClient) Exec(ctx context.Context, nameOrID string, options ExecOptions) (int, error) {
+	args := []string{"exec"}
+	if options.Stdin != nil {
+		args = append(args, "--interactive")
+	}
+	if options.TTY {
+		args = append(args, "--tty")
+	}
+	if len(options.User) > 0 {
+		args = append(args, "--user", options.User)
+	}
+	if len(options.WorkingDir) > 0 {
+		args = append(args, "--workdir", options.WorkingDir)
+	}
+	for _, variable := range options.Env {
+		args = append(args, "--env", variable)
+	}
+	args = append(args, nameOrID)
+	args = append(args, options.Command...)
+
+	command := osexec.CommandContext(ctx, client.command, args...)
+	command.Stdin = options.Stdin
+	command.Stdout = options.Stdout
+	command.Stderr = options.Stderr
+	err := command.Run()
+
+	var exitError *osexec.ExitError
+	if errors.As(err, &exitError) {
+		return exitError.ExitCode(), nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
+
+// Logs writes the log output of the given container to stdout and stderr
+func (client *CLIClient) Logs(ctx context.Context, nameOrID string, options LogsOptions, stdout io.Writer, stderr io.Writer) error {
+	args := []string{"logs"}
+	if options.Tail > 0 {
+		args = append(args, "--tail", strconv.Itoa(options.Tail))
+	}
+	if !options.Since.IsZero() {
+		args = append(args, "--since", options.Since.Format(time.RFC3339))
+	}
+	if options.Follow {
+		args = append(args, "--follow")
+	}
+	args = append(args, nameOrID)
+
+	command := osexec.CommandContext(ctx, client.command, args...)
+	command.Stdout = stdout
+	command.Stderr = stderr
+	if err := command.Run(); err != nil && ctx.Err() == nil {
+		return errors.New("failed retrieving logs of container " + nameOrID + ": " + err.Error())
+	}
+	return nil
+}
+
+// Start starts the given container, if it is not running already
+func (client *CLIClient) Start(ctx context.Context, nameOrID string) error {
+	_, err := client.run(ctx, "start", nameOrID)
+	return err
+}
+
+// Stop stops the given container, killing it after the given timeout
+func (client *CLIClient) Stop(ctx context.Context, nameOrID string, timeout time.Duration) error {
+	_, err := client.run(ctx, "stop", "--time", strconv.Itoa(int(timeout.Seconds())), nameOrID)
+	return err
+}
+
+func (client *CLIClient) run(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	command := osexec.CommandContext(ctx, client.command, args...)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return "", errors.New(client.command + " " + args[0] + " failed: " + strings.TrimSpace(stderr.String()+" "+err.Error()))
+	}
+	return stdout.String(), nil
+}
+
+// parseLabels parses labels formatted like "key=value,other=value", where values may contain commas
+func parseLabels(labels string) map[string]string {
+	result := map[string]string{}
+	lastKey := ""
+	for _, part := range strings.Split(labels, ",") {
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			if len(lastKey) > 0 {
+				result[lastKey] += "," + part
+			}
+			continue
+		}
+		result[key] = value
+		lastKey = key
+	}
+	return result
+}
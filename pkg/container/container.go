@@ -0,0 +1,94 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned if a container does not exist
+var ErrNotFound = errors.New("container not found")
+
+const (
+	HealthNone      = ""
+	HealthStarting  = "starting"
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// Container describes a container as seen by the container engine
+type Container struct {
+	ID       string
+	Name     string
+	Image    string
+	State    string
+	Health   string
+	Labels   map[string]string
+	Networks []string
+}
+
+// IsRunning returns true if the container is running
+func (container Container) IsRunning() bool {
+	return container.State == "running"
+}
+
+// ListOptions restricts the containers returned by List. Stopped containers are only included if All is set.
+type ListOptions struct {
+	All     bool
+	Name    string
+	Network string
+	Labels  map[string]string
+}
+
+// ExecOptions describes a command to be executed in a running container. Stdin is only attached if it is
+// not nil, TTY allocates a pseudo terminal and expects Stdin to be a terminal.
+type ExecOptions struct {
+	Command    []string
+	Env        []string
+	User       string
+	WorkingDir string
+	TTY        bool
+	Stdin      io.Reader
+	Stdout     io.Writer
+	Stderr     io.Writer
+}
+
+// LogsOptions describes which log lines are returned by Logs. A Tail of 0 returns all lines.
+type LogsOptions struct {
+	Tail   int
+	Since  time.Time
+	Follow bool
+}
+
+// Client provides access to the containers managed by a container engine
+type Client interface {
+	// List returns the containers matching the given options
+	List(ctx context.Context, options ListOptions) ([]Container, error)
+	// Inspect returns the container with the given name or id, or ErrNotFound
+	Inspect(ctx context.Context, nameOrID string) (*Container, error)
+	// Health returns the health status of the given container, which is HealthNone if it has no health check
+	Health(ctx context.Context, nameOrID string) (string, error)
+	// Exec runs a command in the given container and returns its exit code
+	Exec(ctx context.Context, nameOrID string, options ExecOptions) (int, error)
+	// Logs writes the log output of the given container to stdout and stderr
+	Logs(ctx context.Context, nameOrID string, options LogsOptions, stdout io.Writer, stderr io.Writer) error
+	// Start starts the given container, if it is not running already
+	Start(ctx context.Context, nameOrID string) error
+	// Stop stops the given container, killing it after the given timeout
+	Stop(ctx context.Context, nameOrID string, timeout time.Duration) error
+}
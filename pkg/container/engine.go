@@ -0,0 +1,475 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EngineClient talks to the Docker Engine API (or a compatible API) through a Unix socket
+type EngineClient struct {
+	socketPath string
+	httpClient *http.Client
+}
+
+type engineContainerSummary struct {
+	ID              string            `json:"Id"`
+	Names           []string          `json:"Names"`
+	Image           string            `json:"Image"`
+	State           string            `json:"State"`
+	Status          string            `json:"Status"`
+	Labels          map[string]string `json:"Labels"`
+	NetworkSettings struct {
+		Networks map[string]interface{} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+type engineContainerDetails struct {
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Config struct {
+		Image  string            `json:"Image"`
+		Labels map[string]string `json:"Labels"`
+		Tty    bool              `json:"Tty"`
+	} `json:"Config"`
+	State struct {
+		Status string `json:"Status"`
+		Health *struct {
+			Status string `json:"Status"`
+		} `json:"Health"`
+	} `json:"State"`
+	NetworkSettings struct {
+		Networks map[string]interface{} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+// NewEngineClient returns a client for the container engine listening on the given Unix socket
+func NewEngineClient(socketPath string) *EngineClient {
+	client := &EngineClient{socketPath: socketPath}
+	client.httpClient = &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return client.dial(ctx)
+			},
+		},
+	}
+	return client
+}
+
+// List returns the containers matching the given options
+func (client *EngineClient) List(ctx context.Context, options ListOptions) ([]Container, error) {
+	filters := map[string][]string{}
+	if len(options.Name) > 0 {
+		filters["name"] = []string{"^/?" + regexp.QuoteMeta(options.Name) + "$"}
+	}
+	if len(options.Network) > 0 {
+		filters["network"] = []string{options.Network}
+	}
+	for key, value := range options.Labels {
+		filters["label"] = append(filters["label"], key+"="+value)
+	}
+
+	query := url.Values{}
+	if options.All {
+		query.Set("all", "1")
+	}
+	if len(filters) > 0 {
+		encodedFilters, err := json.Marshal(filters)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("filters", string(encodedFilters))
+	}
+
+	var summaries []engineContainerSummary
+	if err := client.getJSON(ctx, "/containers/json", query, &summaries); err != nil {
+		return nil, err
+	}
+
+	containers := []Container{}
+	for _, summary := range summaries {
+		container := Container{
+			ID:       summary.ID,
+			Image:    summary.Image,
+			State:    summary.State,
+			Health:   parseHealthFromStatus(summary.Status),
+			Labels:   summary.Labels,
+			Networks: sortedKeys(summary.NetworkSettings.Networks),
+		}
+		if len(summary.Names) > 0 {
+			container.Name = strings.TrimPrefix(summary.Names[0], "/")
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// Inspect returns the container with the given name or id, or ErrNotFound
+func (client *EngineClient) Inspect(ctx context.Context, nameOrID string) (*Container, error) {
+	details, err := client.inspect(ctx, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	container := &Container{
+		ID:       details.ID,
+		Name:     strings.TrimPrefix(details.Name, "/"),
+		Image:    details.Config.Image,
+		State:    details.State.Status,
+		Labels:   details.Config.Labels,
+		Networks: sortedKeys(details.NetworkSettings.Networks),
+	}
+	if details.State.Health != nil {
+		container.Health = details.State.Health.Status
+	}
+	return container, nil
+}
+
+// Health returns the health status of the given container, which is HealthNone if it has no health check
+func (client *EngineClient) Health(ctx context.Context, nameOrID string) (string, error) {
+	container, err := client.Inspect(ctx, nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return container.Health, nil
+}
+
+// Exec runs a command in the given container and returns its exit code
+func (client *EngineClient) Exec(ctx context.Context, nameOrID string, options ExecOptions) (int, error) {
+	var execInstance struct {
+		ID string `json:"Id"`
+	}
+	err := client.postJSON(ctx, "/containers/"+url.PathEscape(nameOrID)+"/exec", nil, map[string]interface{}{
+		"AttachStdin":  options.Stdin != nil,
+		"AttachStdout": true,
+		"AttachStderr": true,
+		"Tty":          options.TTY,
+		"Cmd":          options.Command,
+		"Env":          options.Env,
+		"User":         options.User,
+		"WorkingDir":   options.WorkingDir,
+	}, &execInstance)
+	if err != nil {
+		return -1, err
+	}
+
+	connection, reader, err := client.hijack(ctx, "/exec/"+execInstance.ID+"/start", map[string]interface{}{
+		"Detach": false,
+		"Tty":    options.TTY,
+	})
+	if err != nil {
+		return -1, err
+	}
+	defer connection.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = connection.Close()
+		case <-done:
+		}
+	}()
+
+	stdout, stderr := options.Stdout, options.Stderr
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+
+	if options.TTY {
+		if stdinFile, ok := options.Stdin.(*os.File); ok {
+			restore, err := makeTerminalRaw(stdinFile.Fd())
+			if err == nil {
+				defer restore()
+			}
+			stopResizing := forwardTerminalSize(stdinFile.Fd(), func(width uint16, height uint16) {
+				query := url.Values{"w": {strconv.Itoa(int(width))}, "h": {strconv.Itoa(int(height))}}
+				_ = client.post(ctx, "/exec/"+execInstance.ID+"/resize", query)
+			})
+			defer stopResizing()
+		}
+	}
+
+	if options.Stdin != nil {
+		go func() {
+			_, _ = io.Copy(connection, options.Stdin)
+			if closeWriter, ok := connection.(interface{ CloseWrite() error }); ok {
+				_ = closeWriter.CloseWrite()
+			}
+		}()
+	}
+
+	if options.TTY {
+		_, err = io.Copy(stdout, reader)
+	} else {
+		err = demultiplexStream(reader, stdout, stderr)
+	}
+	if err != nil && ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+		return -1, err
+	}
+	if ctx.Err() != nil {
+		return -1, ctx.Err()
+	}
+
+	var execState struct {
+		ExitCode int  `json:"ExitCode"`
+		Running  bool `json:"Running"`
+	}
+	if err := client.getJSON(ctx, "/exec/"+execInstance.ID+"/json", nil, &execState); err != nil {
+		return -1, err
+	}
+	return execState.ExitCode, nil
+}
+
+// Logs writes the log output of the given container to stdout and stderr
+func (client *EngineClient) Logs(ctx context.Context, nameOrID string, options LogsOptions, stdout io.Writer, stderr io.Writer) error {
+	details, err := client.inspect(ctx, nameOrID)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{"stdout": {"1"}, "stderr": {"1"}}
+	if options.Tail > 0 {
+		query.Set("tail", strconv.Itoa(options.Tail))
+	}
+	if !options.Since.IsZero() {
+		query.Set("since", strconv.FormatInt(options.Since.Unix(), 10))
+	}
+	if options.Follow {
+		query.Set("follow", "1")
+	}
+
+	response, err := client.request(ctx, http.MethodGet, "/containers/"+url.PathEscape(nameOrID)+"/logs", query, nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if details.Config.Tty {
+		_, err = io.Copy(stdout, response.Body)
+	} else {
+		err = demultiplexStream(response.Body, stdout, stderr)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// Start starts the given container, if it is not running already
+func (client *EngineClient) Start(ctx context.Context, nameOrID string) error {
+	return client.post(ctx, "/containers/"+url.PathEscape(nameOrID)+"/start", nil)
+}
+
+// Stop stops the given container, killing it after the given timeout
+func (client *EngineClient) Stop(ctx context.Context, nameOrID string, timeout time.Duration) error {
+	query := url.Values{"t": {strconv.Itoa(int(timeout.Seconds()))}}
+	return client.post(ctx, "/containers/"+url.PathEscape(nameOrID)+"/stop", query)
+}
+
+func (client *EngineClient) inspect(ctx context.Context, nameOrID string) (*engineContainerDetails, error) {
+	var details engineContainerDetails
+	if err := client.getJSON(ctx, "/containers/"+url.PathEscape(nameOrID)+"/json", nil, &details); err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+func (client *EngineClient) dial(ctx context.Context) (net.Conn, error) {
+	var dialer net.Dialer
+	connection, err := dialer.DialContext(ctx, "unix", client.socketPath)
+	if err != nil {
+		return nil, errors.New("failed connecting to the container engine at " + client.socketPath + ", maybe the Docker daemon is not running: " + err.Error())
+	}
+	return connection, nil
+}
+
+func (client *EngineClient) request(ctx context.Context, method string, path string, query url.Values, body interface{}) (*http.Response, error) {
+	request, err := newEngineRequest(ctx, method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		var urlError *url.Error
+		if errors.As(err, &urlError) {
+			return nil, urlError.Err
+		}
+		return nil, err
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		defer response.Body.Close()
+		return nil, newResponseError(response)
+	}
+	return response, nil
+}
+
+func (client *EngineClient) getJSON(ctx context.Context, path string, query url.Values, target interface{}) error {
+	response, err := client.request(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	return json.NewDecoder(response.Body).Decode(target)
+}
+
+func (client *EngineClient) postJSON(ctx context.Context, path string, query url.Values, body interface{}, target interface{}) error {
+	response, err := client.request(ctx, http.MethodPost, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	return json.NewDecoder(response.Body).Decode(target)
+}
+
+func (client *EngineClient) post(ctx context.Context, path string, query url.Values) error {
+	response, err := client.request(ctx, http.MethodPost, path, query, nil)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	return response.Body.Close()
+}
+
+// hijack sends a request which upgrades the connection to a raw stream, as used for attaching to an exec instance
+func (client *EngineClient) hijack(ctx context.Context, path string, body interface{}) (net.Conn, *bufio.Reader, error) {
+	request, err := newEngineRequest(ctx, http.MethodPost, path, nil, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	request.Header.Set("Connection", "Upgrade")
+	request.Header.Set("Upgrade", "tcp")
+
+	connection, err := client.dial(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := request.Write(connection); err != nil {
+		_ = connection.Close()
+		return nil, nil, err
+	}
+
+	reader := bufio.NewReader(connection)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		_ = connection.Close()
+		return nil, nil, err
+	}
+	if response.StatusCode != http.StatusSwitchingProtocols && response.StatusCode != http.StatusOK {
+		defer connection.Close()
+		return nil, nil, newResponseError(response)
+	}
+	return connection, reader, nil
+}
+
+func newEngineRequest(ctx context.Context, method string, path string, query url.Values, body interface{}) (*http.Request, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		encodedBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(encodedBody)
+	}
+
+	requestURL := "http://localhost" + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	return request, nil
+}
+
+func newResponseError(response *http.Response) error {
+	var engineError struct {
+		Message string `json:"message"`
+	}
+	_ = json.NewDecoder(response.Body).Decode(&engineError)
+	if response.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, engineError.Message)
+	}
+	return errors.New("container engine returned " + response.Status + ": " + engineError.Message)
+}
+
+// demultiplexStream splits a stream of a container without TTY into stdout and stderr. Each frame starts with
+// an 8 byte header, consisting of the stream type and the frame size.
+func demultiplexStream(reader io.Reader, stdout io.Writer, stderr io.Writer) error {
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		target := stdout
+		if header[0] == 2 {
+			target = stderr
+		}
+		if _, err := io.CopyN(target, reader, int64(binary.BigEndian.Uint32(header[4:]))); err != nil {
+			return err
+		}
+	}
+}
+
+// parseHealthFromStatus extracts the health status from a status like "Up 5 minutes (healthy)"
+func parseHealthFromStatus(status string) string {
+	switch {
+	case strings.Contains(status, "(healthy)"):
+		return HealthHealthy
+	case strings.Contains(status, "(unhealthy)"):
+		return HealthUnhealthy
+	case strings.Contains(status, "(health: starting)"):
+		return HealthStarting
+	}
+	return HealthNone
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
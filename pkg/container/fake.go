@@ -0,0 +1,186 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fake is an in-memory implementation of Client, meant for tests. Commands passed to Exec are recorded in
+// Executions and handled by ExecHandler, if set.
+type Fake struct {
+	Containers  []Container
+	LogOutput   map[string]string
+	ExecHandler func(nameOrID string, options ExecOptions) (int, error)
+	Executions  []FakeExecution
+
+	mutex sync.Mutex
+}
+
+// FakeExecution is a command which was executed by the Fake client
+type FakeExecution struct {
+	Container string
+	Command   []string
+}
+
+// NewFake returns a fake client knowing the given containers
+func NewFake(containers ...Container) *Fake {
+	return &Fake{
+		Containers: containers,
+		LogOutput:  map[string]string{},
+	}
+}
+
+// List returns the containers matching the given options
+func (fake *Fake) List(ctx context.Context, options ListOptions) ([]Container, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	containers := []Container{}
+	for _, container := range fake.Containers {
+		if !options.All && !container.IsRunning() {
+			continue
+		}
+		if len(options.Name) > 0 && container.Name != options.Name {
+			continue
+		}
+		if len(options.Network) > 0 && !containsString(container.Networks, options.Network) {
+			continue
+		}
+		if !hasLabels(container, options.Labels) {
+			continue
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// Inspect returns the container with the given name or id, or ErrNotFound
+func (fake *Fake) Inspect(ctx context.Context, nameOrID string) (*Container, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	container := fake.find(nameOrID)
+	if container == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, nameOrID)
+	}
+	result := *container
+	return &result, nil
+}
+
+// Health returns the health status of the given container
+func (fake *Fake) Health(ctx context.Context, nameOrID string) (string, error) {
+	container, err := fake.Inspect(ctx, nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return container.Health, nil
+}
+
+// Exec records the command and passes it to ExecHandler
+func (fake *Fake) Exec(ctx context.Context, nameOrID string, options ExecOptions) (int, error) {
+	container, err := fake.Inspect(ctx, nameOrID)
+	if err != nil {
+		return -1, err
+	}
+	if !container.IsRunning() {
+		return -1, fmt.Errorf("container %s is not running", nameOrID)
+	}
+
+	fake.mutex.Lock()
+	fake.Executions = append(fake.Executions, FakeExecution{Container: nameOrID, Command: options.Command})
+	handler := fake.ExecHandler
+	fake.mutex.Unlock()
+
+	if handler == nil {
+		return 0, nil
+	}
+	return handler(nameOrID, options)
+}
+
+// Logs writes the log output defined for the given container to stdout
+func (fake *Fake) Logs(ctx context.Context, nameOrID string, options LogsOptions, stdout io.Writer, stderr io.Writer) error {
+	if _, err := fake.Inspect(ctx, nameOrID); err != nil {
+		return err
+	}
+
+	fake.mutex.Lock()
+	output := fake.LogOutput[nameOrID]
+	fake.mutex.Unlock()
+
+	lines := strings.SplitAfter(output, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if options.Tail > 0 && len(lines) > options.Tail {
+		lines = lines[len(lines)-options.Tail:]
+	}
+	_, err := io.WriteString(stdout, strings.Join(lines, ""))
+	return err
+}
+
+// Start sets the state of the given container to "running"
+func (fake *Fake) Start(ctx context.Context, nameOrID string) error {
+	return fake.setState(nameOrID, "running")
+}
+
+// Stop sets the state of the given container to "exited"
+func (fake *Fake) Stop(ctx context.Context, nameOrID string, timeout time.Duration) error {
+	return fake.setState(nameOrID, "exited")
+}
+
+func (fake *Fake) setState(nameOrID string, state string) error {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	container := fake.find(nameOrID)
+	if container == nil {
+		return fmt.Errorf("%w: %s", ErrNotFound, nameOrID)
+	}
+	container.State = state
+	return nil
+}
+
+func (fake *Fake) find(nameOrID string) *Container {
+	for i := range fake.Containers {
+		if fake.Containers[i].Name == nameOrID || fake.Containers[i].ID == nameOrID {
+			return &fake.Containers[i]
+		}
+	}
+	return nil
+}
+
+func hasLabels(container Container, labels map[string]string) bool {
+	for key, value := range labels {
+		if container.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
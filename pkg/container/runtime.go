@@ -0,0 +1,201 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"errors"
+	"net"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flownative/localbeach/pkg/exec"
+)
+
+const (
+	RuntimeDocker  = "docker"
+	RuntimePodman  = "podman"
+	RuntimeNerdctl = "nerdctl"
+)
+
+// Runtime describes the container runtime running the Local Beach containers
+type Runtime struct {
+	// Name is one of RuntimeDocker, RuntimePodman or RuntimeNerdctl
+	Name string
+	// Command is the command line tool of the runtime
+	Command string
+	// SocketPath is the path of the Docker compatible API socket on this host, empty if there is none
+	SocketPath string
+	// MountSocketPath is the path of the API socket as seen by containers, which is different from SocketPath
+	// if the runtime runs in a virtual machine
+	MountSocketPath string
+}
+
+// DetectRuntime returns the runtime with the given name, or the runtime installed on this host if no name is
+// given. An empty socket path means that the default socket path of the runtime is used.
+func DetectRuntime(name string, socketPath string) (*Runtime, error) {
+	if len(name) == 0 {
+		name = detectRuntimeName()
+	}
+
+	runtime := &Runtime{
+		Name:    name,
+		Command: name,
+	}
+	switch name {
+	case RuntimeDocker:
+		runtime.SocketPath = detectDockerSocketPath()
+		runtime.MountSocketPath = runtime.SocketPath
+		if goruntime.GOOS == "darwin" {
+			// Docker Desktop, Colima and friends provide the socket at the default location inside their virtual machine
+			runtime.MountSocketPath = "/var/run/docker.sock"
+		}
+	case RuntimePodman:
+		runtime.SocketPath, runtime.MountSocketPath = detectPodmanSocketPaths()
+	case RuntimeNerdctl:
+		// nerdctl talks to containerd directly and provides no Docker compatible API socket
+	default:
+		return nil, errors.New("unsupported container runtime \"" + name + "\", must be one of docker, podman or nerdctl")
+	}
+
+	if len(socketPath) > 0 {
+		runtime.SocketPath = socketPath
+		runtime.MountSocketPath = socketPath
+	}
+	return runtime, nil
+}
+
+// Client returns a client for the containers of this runtime, using the API socket if it can be connected to, and
+// the command line tool otherwise
+func (runtime *Runtime) Client() Client {
+	if len(runtime.SocketPath) > 0 {
+		connection, err := net.DialTimeout("unix", runtime.SocketPath, time.Second)
+		if err == nil {
+			_ = connection.Close()
+			return NewEngineClient(runtime.SocketPath)
+		}
+	}
+	return NewCLIClient(runtime.Command)
+}
+
+// Version returns the version reported by the command line tool of the runtime
+func (runtime *Runtime) Version() (string, error) {
+	output, err := exec.RunCommand(runtime.Command, []string{"--version"})
+	if err != nil {
+		return "", errors.New("failed running " + runtime.Command + ": " + strings.TrimSpace(output))
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// IsRootless returns true if the runtime runs containers without root privileges
+func (runtime *Runtime) IsRootless() bool {
+	switch runtime.Name {
+	case RuntimeDocker:
+		output, err := exec.RunCommand(runtime.Command, []string{"info", "--format", "{{.SecurityOptions}}"})
+		return err == nil && strings.Contains(output, "rootless")
+	case RuntimePodman:
+		output, err := exec.RunCommand(runtime.Command, []string{"info", "--format", "{{.Host.Security.Rootless}}"})
+		return err == nil && strings.TrimSpace(output) == "true"
+	}
+	return os.Getuid() != 0
+}
+
+func detectRuntimeName() string {
+	if len(os.Getenv("DOCKER_HOST")) > 0 {
+		return RuntimeDocker
+	}
+	if _, err := osexec.LookPath(RuntimeDocker); err == nil {
+		// The podman-docker package provides a "docker" command which actually runs Podman
+		output, err := exec.RunCommand(RuntimeDocker, []string{"--version"})
+		if err == nil && strings.Contains(strings.ToLower(output), "podman") {
+			if _, err := osexec.LookPath(RuntimePodman); err == nil {
+				return RuntimePodman
+			}
+		}
+		return RuntimeDocker
+	}
+	for _, name := range []string{RuntimePodman, RuntimeNerdctl} {
+		if _, err := osexec.LookPath(name); err == nil {
+			return name
+		}
+	}
+	return RuntimeDocker
+}
+
+// detectDockerSocketPath returns the path of the Docker socket on this host, respecting DOCKER_HOST and the active
+// Docker context, which Colima, OrbStack and rootless Docker use for their sockets. An empty path means that Docker
+// is not reachable via a local socket.
+func detectDockerSocketPath() string {
+	if host, isSet := os.LookupEnv("DOCKER_HOST"); isSet && len(host) > 0 {
+		return socketPathFromHost(host)
+	}
+
+	output, err := exec.RunCommand(RuntimeDocker, []string{"context", "inspect", "--format", "{{.Endpoints.docker.Host}}"})
+	if lines := strings.Fields(output); err == nil && len(lines) > 0 {
+		return socketPathFromHost(lines[len(lines)-1])
+	}
+
+	candidates := []string{"/var/run/docker.sock"}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(homeDir, ".docker", "run", "docker.sock"))
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return candidates[0]
+}
+
+// socketPathFromHost returns the socket path of the given Docker host, like "unix:///var/run/docker.sock", or an
+// empty path for other hosts, like "tcp://" or "ssh://" ones
+func socketPathFromHost(host string) string {
+	if !strings.HasPrefix(host, "unix://") {
+		return ""
+	}
+	return strings.TrimPrefix(host, "unix://")
+}
+
+func detectPodmanSocketPaths() (string, string) {
+	var mountSocketPath string
+	output, err := exec.RunCommand(RuntimePodman, []string{"info", "--format", "{{.Host.RemoteSocket.Path}}"})
+	if lines := strings.Fields(output); err == nil && len(lines) > 0 {
+		mountSocketPath = strings.TrimPrefix(lines[len(lines)-1], "unix://")
+	}
+	if len(mountSocketPath) == 0 {
+		mountSocketPath = "/run/podman/podman.sock"
+		if os.Getuid() != 0 {
+			runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+			if len(runtimeDir) == 0 {
+				runtimeDir = filepath.Join("/run/user", strconv.Itoa(os.Getuid()))
+			}
+			mountSocketPath = filepath.Join(runtimeDir, "podman", "podman.sock")
+		}
+	}
+
+	socketPath := mountSocketPath
+	if goruntime.GOOS == "darwin" {
+		// On macOS, Podman runs in a virtual machine and forwards its socket to a different path on the host
+		output, err := exec.RunCommand(RuntimePodman, []string{"machine", "inspect", "--format", "{{.ConnectionInfo.PodmanSocket.Path}}"})
+		if lines := strings.Fields(output); err == nil && len(lines) > 0 {
+			socketPath = lines[0]
+		}
+	}
+	return socketPath, mountSocketPath
+}
@@ -0,0 +1,71 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestSocketPathFromHost(t *testing.T) {
+	tests := map[string]string{
+		"unix:///var/run/docker.sock":                     "/var/run/docker.sock",
+		"unix:///Users/beach/.colima/default/docker.sock": "/Users/beach/.colima/default/docker.sock",
+		"tcp://127.0.0.1:2375":                            "",
+		"ssh://beach@remote":                              "",
+	}
+	for host, expected := range tests {
+		if socketPath := socketPathFromHost(host); socketPath != expected {
+			t.Errorf("expected %q for %q, got %q", expected, host, socketPath)
+		}
+	}
+}
+
+func TestDetectDockerSocketPathRespectsDockerHost(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "unix:///run/user/1000/docker.sock")
+	if socketPath := detectDockerSocketPath(); socketPath != "/run/user/1000/docker.sock" {
+		t.Errorf("expected the socket of DOCKER_HOST, got %q", socketPath)
+	}
+
+	t.Setenv("DOCKER_HOST", "tcp://127.0.0.1:2375")
+	if socketPath := detectDockerSocketPath(); socketPath != "" {
+		t.Errorf("expected no socket for a TCP host, got %q", socketPath)
+	}
+}
+
+func TestClientFallsBackToCommandLineTool(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "docker.sock")
+	runtime := &Runtime{Name: RuntimeDocker, Command: "docker", SocketPath: socketPath}
+	if _, ok := runtime.Client().(*CLIClient); !ok {
+		t.Errorf("expected a command line client without a socket, got %T", runtime.Client())
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("cannot create a unix socket: %v", err)
+	}
+	defer func(listener net.Listener) {
+		_ = listener.Close()
+	}(listener)
+	if _, ok := runtime.Client().(*EngineClient); !ok {
+		t.Errorf("expected an engine client with a reachable socket, got %T", runtime.Client())
+	}
+
+	runtime.SocketPath = ""
+	if _, ok := runtime.Client().(*CLIClient); !ok {
+		t.Errorf("expected a command line client without a socket path, got %T", runtime.Client())
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+type terminalWindowSize struct {
+	Rows    uint16
+	Columns uint16
+	Width   uint16
+	Height  uint16
+}
+
+// makeTerminalRaw puts the terminal into raw mode, so that keys are passed to the container unprocessed, and
+// returns a function which restores the previous mode
+func makeTerminalRaw(fd uintptr) (func(), error) {
+	var previousState syscall.Termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlReadTermios, uintptr(unsafe.Pointer(&previousState))); errno != 0 {
+		return nil, errno
+	}
+
+	rawState := previousState
+	rawState.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	rawState.Oflag &^= syscall.OPOST
+	rawState.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	rawState.Cflag &^= syscall.CSIZE | syscall.PARENB
+	rawState.Cflag |= syscall.CS8
+	rawState.Cc[syscall.VMIN] = 1
+	rawState.Cc[syscall.VTIME] = 0
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlWriteTermios, uintptr(unsafe.Pointer(&rawState))); errno != 0 {
+		return nil, errno
+	}
+
+	return func() {
+		_, _, _ = syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlWriteTermios, uintptr(unsafe.Pointer(&previousState)))
+	}, nil
+}
+
+// forwardTerminalSize calls resize with the current size of the terminal and again whenever it changes,
+// until the returned function is called
+func forwardTerminalSize(fd uintptr, resize func(width uint16, height uint16)) func() {
+	resizeToCurrentSize := func() {
+		var windowSize terminalWindowSize
+		if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&windowSize))); errno == 0 {
+			resize(windowSize.Columns, windowSize.Rows)
+		}
+	}
+	resizeToCurrentSize()
+
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGWINCH)
+	go func() {
+		for {
+			select {
+			case <-signals:
+				resizeToCurrentSize()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -15,6 +15,7 @@
 package exec
 
 import (
+	"io"
 	"os"
 	"os/exec"
 )
@@ -27,6 +28,14 @@ func RunCommand(command string, args []string) (string, error) {
 	return string(out), err
 }
 
+// RunCommandWithEnv executes a given command with the given environment
+func RunCommandWithEnv(command string, args []string, env []string) (string, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Env = env
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
 // RunInteractiveCommand runs the given command interactively, with stdin/stdout/stderr connected
 func RunInteractiveCommand(command string, args []string) error {
 	cmd := exec.Command(command, args...)
@@ -40,3 +49,33 @@ func RunInteractiveCommand(command string, args []string) error {
 	}
 	return cmd.Wait()
 }
+
+// RunInteractiveCommandWithEnv runs the given command interactively with the given environment
+func RunInteractiveCommandWithEnv(command string, args []string, env []string) error {
+	cmd := exec.Command(command, args...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+	return cmd.Wait()
+}
+
+// RunPipedCommand runs the given command with stdin and stdout connected to the given reader and writer,
+// stderr is passed through to the terminal
+func RunPipedCommand(command string, args []string, stdin io.Reader, stdout io.Writer) error {
+	cmd := exec.Command(command, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+	return cmd.Wait()
+}
@@ -27,7 +27,10 @@ import (
 var OldBase = ""
 var Base = ""
 var Certificates = ""
+var CertificateAuthority = ""
 var Database = ""
+var Postgres = ""
+var Snapshots = ""
 
 func init() {
 	homeDir, err := os.UserHomeDir()
@@ -40,5 +43,8 @@ func init() {
 	OldBase = filepath.Join(homeDir, "Library", "Application Support", "Flownative", "Local Beach")
 	Base = filepath.Join(homeDir, ".LocalBeach")
 	Certificates = filepath.Join(Base, "Certificates")
+	CertificateAuthority = filepath.Join(Base, "CertificateAuthority")
 	Database = filepath.Join(Base, "MariaDB")
+	Postgres = filepath.Join(Base, "PostgreSQL")
+	Snapshots = filepath.Join(Base, "Snapshots")
 }
@@ -0,0 +1,140 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Project is a Local Beach project known on this computer
+type Project struct {
+	Name          string     `json:"name"`
+	RootPath      string     `json:"rootPath"`
+	RegisteredAt  time.Time  `json:"registeredAt"`
+	LastStartedAt *time.Time `json:"lastStartedAt,omitempty"`
+}
+
+// Registry contains the Local Beach projects known on this computer, it is written by "beach init" and "beach start"
+type Registry struct {
+	Projects []Project `json:"projects"`
+
+	pathAndFilename string
+	changed         bool
+}
+
+// Load reads the registry from the given file, an empty registry is returned if the file does not exist yet
+func Load(pathAndFilename string) (*Registry, error) {
+	registry := &Registry{pathAndFilename: pathAndFilename}
+
+	content, err := os.ReadFile(pathAndFilename)
+	if errors.Is(err, os.ErrNotExist) {
+		return registry, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed loading project registry %v: %v", pathAndFilename, err)
+	}
+	if err := json.Unmarshal(content, registry); err != nil {
+		return nil, fmt.Errorf("failed parsing project registry %v: %v", pathAndFilename, err)
+	}
+	return registry, nil
+}
+
+// Save writes the registry to the file it was loaded from
+func (registry *Registry) Save() error {
+	sort.Slice(registry.Projects, func(i, j int) bool {
+		return registry.Projects[i].Name < registry.Projects[j].Name
+	})
+	content, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(registry.pathAndFilename), 0755); err != nil {
+		return err
+	}
+
+	// The registry is written to a temporary file first and then renamed, so that an interrupted write or a
+	// concurrent beach command never sees a truncated registry
+	file, err := os.CreateTemp(filepath.Dir(registry.pathAndFilename), filepath.Base(registry.pathAndFilename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), registry.pathAndFilename)
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	registry.changed = false
+	return nil
+}
+
+// Changed returns true if Register or Unregister changed the registry since it was loaded or saved
+func (registry *Registry) Changed() bool {
+	return registry.changed
+}
+
+// Register adds the project with the given root path or updates its name
+func (registry *Registry) Register(name string, rootPath string) *Project {
+	for i := range registry.Projects {
+		if registry.Projects[i].RootPath == rootPath {
+			if registry.Projects[i].Name != name {
+				registry.Projects[i].Name = name
+				registry.changed = true
+			}
+			return &registry.Projects[i]
+		}
+	}
+	registry.Projects = append(registry.Projects, Project{Name: name, RootPath: rootPath, RegisteredAt: time.Now()})
+	registry.changed = true
+	return &registry.Projects[len(registry.Projects)-1]
+}
+
+// Unregister removes the project with the given root path
+func (registry *Registry) Unregister(rootPath string) {
+	var projects []Project
+	for _, project := range registry.Projects {
+		if project.RootPath != rootPath {
+			projects = append(projects, project)
+		}
+	}
+	if len(projects) != len(registry.Projects) {
+		registry.changed = true
+	}
+	registry.Projects = projects
+}
+
+// FindByName returns the projects with the given name
+func (registry *Registry) FindByName(name string) []Project {
+	var projects []Project
+	for _, project := range registry.Projects {
+		if project.Name == name {
+			projects = append(projects, project)
+		}
+	}
+	return projects
+}
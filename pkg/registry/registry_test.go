@@ -0,0 +1,94 @@
+// Copyright 2019-2025 Robert Lemke, Karsten Dambekalns, Christian Müller
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "LocalBeach")
+	pathAndFilename := filepath.Join(directory, "projects.json")
+
+	registry, err := Load(pathAndFilename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	registry.Register("second", "/projects/second")
+	registry.Register("first", "/projects/first")
+	if err := registry.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	registry.Unregister("/projects/second")
+	if err := registry.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loadedRegistry, err := Load(pathAndFilename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(loadedRegistry.Projects) != 1 || loadedRegistry.Projects[0].Name != "first" || loadedRegistry.Projects[0].RootPath != "/projects/first" {
+		t.Errorf("expected only the first project, got %+v", loadedRegistry.Projects)
+	}
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary files to be left, got %v", entries)
+	}
+	info, err := os.Stat(pathAndFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("expected mode 0644, got %v", info.Mode().Perm())
+	}
+}
+
+func TestChanged(t *testing.T) {
+	registry, err := Load(filepath.Join(t.TempDir(), "projects.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	registry.Register("first", "/projects/first")
+	if !registry.Changed() {
+		t.Error("expected a new project to change the registry")
+	}
+	if err := registry.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	registry.Register("first", "/projects/first")
+	registry.Unregister("/projects/unknown")
+	if registry.Changed() {
+		t.Error("expected registering a known project and unregistering an unknown one to leave the registry unchanged")
+	}
+	registry.Register("renamed", "/projects/first")
+	if !registry.Changed() {
+		t.Error("expected a renamed project to change the registry")
+	}
+	if err := registry.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	registry.Unregister("/projects/first")
+	if !registry.Changed() {
+		t.Error("expected a removed project to change the registry")
+	}
+}